@@ -0,0 +1,136 @@
+package telegramsender
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// RetryStrategy selects the jitter algorithm sendWithRetry uses to space out
+// retries when Telegram hasn't supplied its own Retry-After.
+type RetryStrategy string
+
+const (
+	RetryStrategyExponential        RetryStrategy = "exponential"
+	RetryStrategyFullJitter         RetryStrategy = "full_jitter"
+	RetryStrategyDecorrelatedJitter RetryStrategy = "decorrelated_jitter"
+)
+
+// Backoff computes how long sendWithRetry should wait before its next
+// attempt. A serverRetryDelay greater than zero (from a 429's retry_after or
+// the HTTP Retry-After header) always wins over the strategy's own jitter.
+// Implementations carry state across attempts, so a Backoff must be
+// constructed fresh per sendWithRetry call via newBackoff rather than shared
+// across concurrent sends.
+type Backoff interface {
+	NextBackoff(attempt int, serverRetryDelay time.Duration) time.Duration
+}
+
+// newBackoff builds the Backoff configured by cfg.RetryStrategy, defaulting
+// to full jitter when cfg.RetryStrategy is unset or unrecognized.
+func newBackoff(cfg *Config) Backoff {
+	switch cfg.RetryStrategy {
+	case RetryStrategyExponential:
+		return newExponentialBackoff(cfg)
+	case RetryStrategyDecorrelatedJitter:
+		return &DecorrelatedJitterBackoff{base: cfg.RetryInitialBackoff, ceiling: cfg.RetryMaxBackoff}
+	default:
+		return &FullJitterBackoff{base: cfg.RetryInitialBackoff, ceiling: cfg.RetryMaxBackoff}
+	}
+}
+
+// ExponentialBackoff wraps github.com/cenkalti/backoff/v4's
+// ExponentialBackOff, the well-tested randomized-exponential algorithm most
+// HTTP clients use, with the Telegram-specific serverRetryDelay override.
+type ExponentialBackoff struct {
+	bo *backoff.ExponentialBackOff
+}
+
+func newExponentialBackoff(cfg *Config) *ExponentialBackoff {
+	bo := backoff.NewExponentialBackOff()
+	bo.InitialInterval = cfg.RetryInitialBackoff
+	bo.MaxInterval = cfg.RetryMaxBackoff
+	bo.Multiplier = cfg.RetryBackoffFactor
+	bo.RandomizationFactor = cfg.RetryRandomizationFactor
+	bo.MaxElapsedTime = cfg.RetryMaxElapsedTime
+	bo.Reset()
+	return &ExponentialBackoff{bo: bo}
+}
+
+func (e *ExponentialBackoff) NextBackoff(attempt int, serverRetryDelay time.Duration) time.Duration {
+	if serverRetryDelay > 0 {
+		return serverRetryDelay
+	}
+	if next := e.bo.NextBackOff(); next != backoff.Stop {
+		return next
+	}
+	return e.bo.MaxInterval
+}
+
+// FullJitterBackoff implements the "full jitter" algorithm from
+// https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/:
+// sleep = rand(0, min(ceiling, base*2^attempt)).
+type FullJitterBackoff struct {
+	base    time.Duration
+	ceiling time.Duration
+}
+
+func (f *FullJitterBackoff) NextBackoff(attempt int, serverRetryDelay time.Duration) time.Duration {
+	if serverRetryDelay > 0 {
+		return serverRetryDelay
+	}
+	upper := exponentialCeiling(f.base, f.ceiling, attempt)
+	return time.Duration(rand.Int63n(int64(upper) + 1))
+}
+
+// DecorrelatedJitterBackoff implements the "decorrelated jitter" algorithm
+// from the same source: sleep = min(ceiling, rand(base, prev*3)), tracking
+// prev across attempts within a single sendWithRetry loop.
+type DecorrelatedJitterBackoff struct {
+	base    time.Duration
+	ceiling time.Duration
+
+	mu   sync.Mutex
+	prev time.Duration
+}
+
+func (d *DecorrelatedJitterBackoff) NextBackoff(attempt int, serverRetryDelay time.Duration) time.Duration {
+	if serverRetryDelay > 0 {
+		return serverRetryDelay
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	prev := d.prev
+	if prev == 0 {
+		prev = d.base
+	}
+
+	span := prev*3 - d.base
+	if span <= 0 {
+		span = 1
+	}
+	sleep := d.base + time.Duration(rand.Int63n(int64(span)))
+	if sleep > d.ceiling {
+		sleep = d.ceiling
+	}
+	d.prev = sleep
+	return sleep
+}
+
+// exponentialCeiling returns min(ceiling, base*2^attempt), saturating at
+// ceiling instead of overflowing for large attempt counts.
+func exponentialCeiling(base, ceiling time.Duration, attempt int) time.Duration {
+	if attempt < 0 {
+		attempt = 0
+	}
+	scaled := float64(base) * math.Pow(2, float64(attempt))
+	if scaled <= 0 || scaled > float64(ceiling) {
+		return ceiling
+	}
+	return time.Duration(scaled)
+}
@@ -0,0 +1,67 @@
+package telegramsender
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExponentialCeiling(t *testing.T) {
+	base := 100 * time.Millisecond
+	ceiling := 10 * time.Second
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: -1, want: base},
+		{attempt: 0, want: base},
+		{attempt: 1, want: 200 * time.Millisecond},
+		{attempt: 3, want: 800 * time.Millisecond},
+		{attempt: 100, want: ceiling},
+	}
+
+	for _, tt := range tests {
+		if got := exponentialCeiling(base, ceiling, tt.attempt); got != tt.want {
+			t.Errorf("exponentialCeiling(%v, %v, %d) = %v, want %v", base, ceiling, tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestFullJitterBackoffWithinBounds(t *testing.T) {
+	b := &FullJitterBackoff{base: 100 * time.Millisecond, ceiling: time.Second}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		upper := exponentialCeiling(b.base, b.ceiling, attempt)
+		for i := 0; i < 20; i++ {
+			got := b.NextBackoff(attempt, 0)
+			if got < 0 || got > upper {
+				t.Fatalf("NextBackoff(%d) = %v, want in [0, %v]", attempt, got, upper)
+			}
+		}
+	}
+}
+
+func TestFullJitterBackoffHonorsServerRetryDelay(t *testing.T) {
+	b := &FullJitterBackoff{base: 100 * time.Millisecond, ceiling: time.Second}
+	if got := b.NextBackoff(5, 3*time.Second); got != 3*time.Second {
+		t.Errorf("NextBackoff with server retry delay = %v, want 3s", got)
+	}
+}
+
+func TestDecorrelatedJitterBackoffWithinBounds(t *testing.T) {
+	d := &DecorrelatedJitterBackoff{base: 100 * time.Millisecond, ceiling: time.Second}
+
+	for i := 0; i < 50; i++ {
+		got := d.NextBackoff(i, 0)
+		if got < d.base || got > d.ceiling {
+			t.Fatalf("NextBackoff = %v, want in [%v, %v]", got, d.base, d.ceiling)
+		}
+	}
+}
+
+func TestDecorrelatedJitterBackoffHonorsServerRetryDelay(t *testing.T) {
+	d := &DecorrelatedJitterBackoff{base: 100 * time.Millisecond, ceiling: time.Second}
+	if got := d.NextBackoff(0, 2*time.Second); got != 2*time.Second {
+		t.Errorf("NextBackoff with server retry delay = %v, want 2s", got)
+	}
+}
@@ -0,0 +1,93 @@
+package telegramsender
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// chatScoped is implemented by request payloads addressed to a single
+// chat, letting invoke apply the matching per-chat limiter ahead of the
+// global one.
+type chatScoped interface {
+	chatID() int64
+}
+
+// chatLimiterEntry pairs a per-chat limiter with the last time it admitted
+// a request, so idle entries can be evicted to bound memory.
+type chatLimiterEntry struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// chatRateLimiter is a map of per-ChatID rate.Limiters guarded by a single
+// mutex; it is not sharded, so every lookup serializes on mu. Groups and
+// channels (negative chat IDs) get a slower limiter tier than private
+// chats, and limiters idle past idleTTL are evicted lazily.
+type chatRateLimiter struct {
+	mu         sync.Mutex
+	entries    map[int64]*chatLimiterEntry
+	chatLimit  rate.Limit
+	groupLimit rate.Limit
+	idleTTL    time.Duration
+}
+
+func newChatRateLimiter(chatLimit, groupLimit float64, idleTTL time.Duration) *chatRateLimiter {
+	return &chatRateLimiter{
+		entries:    make(map[int64]*chatLimiterEntry),
+		chatLimit:  rate.Limit(chatLimit),
+		groupLimit: rate.Limit(groupLimit),
+		idleTTL:    idleTTL,
+	}
+}
+
+// wait blocks until chatID's per-chat limiter admits a request.
+func (c *chatRateLimiter) wait(ctx context.Context, chatID int64) error {
+	return c.get(chatID).Wait(ctx)
+}
+
+// get returns the limiter for chatID, lazily creating one on first use and
+// sweeping idle entries while it holds the lock.
+func (c *chatRateLimiter) get(chatID int64) *rate.Limiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.evictIdleLocked()
+
+	entry, ok := c.entries[chatID]
+	if !ok {
+		limit := c.chatLimit
+		// Negative chat IDs identify groups, supergroups, and channels,
+		// which Telegram throttles far more slowly than private chats.
+		if chatID < 0 {
+			limit = c.groupLimit
+		}
+		entry = &chatLimiterEntry{limiter: rate.NewLimiter(limit, 1)}
+		c.entries[chatID] = entry
+	}
+	entry.lastUsed = time.Now()
+	return entry.limiter
+}
+
+// len reports how many per-chat limiters are currently held in memory.
+func (c *chatRateLimiter) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// evictIdleLocked removes limiters unused for longer than idleTTL. Callers
+// must hold c.mu.
+func (c *chatRateLimiter) evictIdleLocked() {
+	if c.idleTTL <= 0 {
+		return
+	}
+	cutoff := time.Now().Add(-c.idleTTL)
+	for chatID, entry := range c.entries {
+		if entry.lastUsed.Before(cutoff) {
+			delete(c.entries, chatID)
+		}
+	}
+}
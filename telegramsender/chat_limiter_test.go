@@ -0,0 +1,50 @@
+package telegramsender
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChatRateLimiterGroupVsPrivateTiers(t *testing.T) {
+	l := newChatRateLimiter(1, 0.5, time.Minute)
+
+	private := l.get(12345)
+	group := l.get(-12345)
+
+	if private.Limit() != 1 {
+		t.Errorf("private chat limiter limit = %v, want 1", private.Limit())
+	}
+	if group.Limit() != 0.5 {
+		t.Errorf("group chat limiter limit = %v, want 0.5", group.Limit())
+	}
+}
+
+func TestChatRateLimiterEvictsIdleEntries(t *testing.T) {
+	l := newChatRateLimiter(1, 1, time.Millisecond)
+
+	l.get(1)
+	if got := l.len(); got != 1 {
+		t.Fatalf("len() = %d, want 1", got)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	// get on a different chat triggers the sweep; the idle entry for chat 1
+	// should be gone, leaving just the newly created one for chat 2.
+	l.get(2)
+	if got := l.len(); got != 1 {
+		t.Errorf("len() after eviction = %d, want 1", got)
+	}
+}
+
+func TestChatRateLimiterNoEvictionWhenTTLDisabled(t *testing.T) {
+	l := newChatRateLimiter(1, 1, 0)
+
+	l.get(1)
+	time.Sleep(5 * time.Millisecond)
+	l.get(2)
+
+	if got := l.len(); got != 2 {
+		t.Errorf("len() = %d, want 2 (no eviction with idleTTL<=0)", got)
+	}
+}
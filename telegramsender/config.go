@@ -20,6 +20,13 @@ type Config struct {
 	// Rate limiting
 	RateLimitRequests   float64
 	RateLimitBurst      int
+
+	// Per-chat rate limiting. Telegram enforces roughly 1 msg/sec for
+	// private chats and 20 msg/min for groups/channels, on top of the
+	// ~30 msg/sec global limit above.
+	PerChatRateLimit      float64
+	PerGroupRateLimit     float64
+	PerChatLimiterIdleTTL time.Duration
 	
 	// Circuit breaker
 	BreakerMaxRequests  uint32
@@ -31,7 +38,14 @@ type Config struct {
 	RetryInitialBackoff time.Duration
 	RetryMaxBackoff     time.Duration
 	RetryBackoffFactor  float64
-	
+
+	// RetryStrategy selects the jitter algorithm used once MaxRetries and
+	// the backoff bounds above are applied; see Backoff. RandomizationFactor
+	// and MaxElapsedTime only apply to RetryStrategyExponential.
+	RetryStrategy            RetryStrategy
+	RetryRandomizationFactor float64
+	RetryMaxElapsedTime      time.Duration
+
 	// Logging
 	LogFilePath         string
 }
@@ -47,6 +61,21 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	perChatRateLimit, err := strconv.ParseFloat(getEnv("PER_CHAT_RATE_LIMIT", "1"), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	perGroupRateLimit, err := strconv.ParseFloat(getEnv("PER_GROUP_RATE_LIMIT", "0.333"), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	perChatLimiterIdleTTL, err := time.ParseDuration(getEnv("PER_CHAT_LIMITER_IDLE_TTL", "10m"))
+	if err != nil {
+		return nil, err
+	}
+
 	requestTimeout, err := time.ParseDuration(getEnv("REQUEST_TIMEOUT", "10s"))
 	if err != nil {
 		return nil, err
@@ -102,6 +131,16 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	retryRandomizationFactor, err := strconv.ParseFloat(getEnv("RETRY_RANDOMIZATION_FACTOR", "0.5"), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	retryMaxElapsedTime, err := time.ParseDuration(getEnv("RETRY_MAX_ELAPSED_TIME", "0"))
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		BotToken:            getEnv("BOT_TOKEN", ""),
 		BaseURL:             getEnv("BASE_URL", "https://api.telegram.org"),
@@ -113,7 +152,11 @@ func LoadConfig() (*Config, error) {
 		
 		RateLimitRequests:   rateLimitRequests,
 		RateLimitBurst:      rateLimitBurst,
-		
+
+		PerChatRateLimit:      perChatRateLimit,
+		PerGroupRateLimit:     perGroupRateLimit,
+		PerChatLimiterIdleTTL: perChatLimiterIdleTTL,
+
 		BreakerMaxRequests:  uint32(breakerMaxRequests),
 		BreakerInterval:     breakerInterval,
 		BreakerTimeout:      breakerTimeout,
@@ -122,7 +165,11 @@ func LoadConfig() (*Config, error) {
 		RetryInitialBackoff: retryInitialBackoff,
 		RetryMaxBackoff:     retryMaxBackoff,
 		RetryBackoffFactor:  retryBackoffFactor,
-		
+
+		RetryStrategy:            RetryStrategy(getEnv("RETRY_STRATEGY", string(RetryStrategyFullJitter))),
+		RetryRandomizationFactor: retryRandomizationFactor,
+		RetryMaxElapsedTime:      retryMaxElapsedTime,
+
 		LogFilePath:         getEnv("LOG_FILE_PATH", "logs/telegramsender.log"),
 	}, nil
 }
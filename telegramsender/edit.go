@@ -0,0 +1,146 @@
+package telegramsender
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+/* ---------- types ---------- */
+
+// EditMessageTextRequest is the payload for EditMessageText.
+type EditMessageTextRequest struct {
+	ChatID                int64       `json:"chat_id"`
+	MessageID             int         `json:"message_id"`
+	Text                  string      `json:"text"`
+	ParseMode             string      `json:"parse_mode,omitempty"`
+	DisableWebPagePreview bool        `json:"disable_web_page_preview,omitempty"`
+	ReplyMarkup           interface{} `json:"reply_markup,omitempty"`
+}
+
+func (r EditMessageTextRequest) chatID() int64 { return r.ChatID }
+
+func (r EditMessageTextRequest) withChatID(chatID int64) interface{} {
+	r.ChatID = chatID
+	return r
+}
+
+// EditMessageReplyMarkupRequest is the payload for EditMessageReplyMarkup.
+type EditMessageReplyMarkupRequest struct {
+	ChatID      int64       `json:"chat_id"`
+	MessageID   int         `json:"message_id"`
+	ReplyMarkup interface{} `json:"reply_markup,omitempty"`
+}
+
+func (r EditMessageReplyMarkupRequest) chatID() int64 { return r.ChatID }
+
+func (r EditMessageReplyMarkupRequest) withChatID(chatID int64) interface{} {
+	r.ChatID = chatID
+	return r
+}
+
+// DeleteMessageRequest is the payload for DeleteMessage.
+type DeleteMessageRequest struct {
+	ChatID    int64 `json:"chat_id"`
+	MessageID int   `json:"message_id"`
+}
+
+func (r DeleteMessageRequest) chatID() int64 { return r.ChatID }
+
+func (r DeleteMessageRequest) withChatID(chatID int64) interface{} {
+	r.ChatID = chatID
+	return r
+}
+
+// AnswerCallbackQueryRequest is the payload for AnswerCallbackQuery.
+type AnswerCallbackQueryRequest struct {
+	CallbackQueryID string `json:"callback_query_id"`
+	Text            string `json:"text,omitempty"`
+	ShowAlert       bool   `json:"show_alert,omitempty"`
+	URL             string `json:"url,omitempty"`
+	CacheTime       int    `json:"cache_time,omitempty"`
+}
+
+// ChatAction is one of the action strings accepted by SendChatAction, e.g.
+// ChatActionTyping or ChatActionUploadPhoto.
+type ChatAction string
+
+const (
+	ChatActionTyping          ChatAction = "typing"
+	ChatActionUploadPhoto     ChatAction = "upload_photo"
+	ChatActionRecordVideo     ChatAction = "record_video"
+	ChatActionUploadVideo     ChatAction = "upload_video"
+	ChatActionRecordVoice     ChatAction = "record_voice"
+	ChatActionUploadVoice     ChatAction = "upload_voice"
+	ChatActionUploadDocument  ChatAction = "upload_document"
+	ChatActionFindLocation    ChatAction = "find_location"
+	ChatActionRecordVideoNote ChatAction = "record_video_note"
+	ChatActionUploadVideoNote ChatAction = "upload_video_note"
+)
+
+// SendChatActionRequest is the payload for SendChatAction.
+type SendChatActionRequest struct {
+	ChatID int64      `json:"chat_id"`
+	Action ChatAction `json:"action"`
+}
+
+func (r SendChatActionRequest) chatID() int64 { return r.ChatID }
+
+func (r SendChatActionRequest) withChatID(chatID int64) interface{} {
+	r.ChatID = chatID
+	return r
+}
+
+/* ---------- public methods ---------- */
+
+// EditMessageText changes the text of a previously-sent message.
+func (t *TelegramAPI) EditMessageText(ctx context.Context, request EditMessageTextRequest) (*MessageResult, error) {
+	telegramResp, err := t.sendWithRetry(ctx, "editMessageText", request)
+	if err != nil {
+		return nil, err
+	}
+
+	var msgResult MessageResult
+	if err := json.Unmarshal(telegramResp.Result, &msgResult); err != nil {
+		return nil, fmt.Errorf("failed to parse result: %w", err)
+	}
+
+	return &msgResult, nil
+}
+
+// EditMessageReplyMarkup changes only the inline keyboard of a previously-
+// sent message.
+func (t *TelegramAPI) EditMessageReplyMarkup(ctx context.Context, request EditMessageReplyMarkupRequest) (*MessageResult, error) {
+	telegramResp, err := t.sendWithRetry(ctx, "editMessageReplyMarkup", request)
+	if err != nil {
+		return nil, err
+	}
+
+	var msgResult MessageResult
+	if err := json.Unmarshal(telegramResp.Result, &msgResult); err != nil {
+		return nil, fmt.Errorf("failed to parse result: %w", err)
+	}
+
+	return &msgResult, nil
+}
+
+// DeleteMessage deletes a previously-sent message.
+func (t *TelegramAPI) DeleteMessage(ctx context.Context, request DeleteMessageRequest) error {
+	_, err := t.sendWithRetry(ctx, "deleteMessage", request)
+	return err
+}
+
+// AnswerCallbackQuery sends a response to a callback query originating
+// from an inline keyboard button press, stopping the client's loading
+// spinner and optionally showing a notification or alert.
+func (t *TelegramAPI) AnswerCallbackQuery(ctx context.Context, request AnswerCallbackQueryRequest) error {
+	_, err := t.sendWithRetry(ctx, "answerCallbackQuery", request)
+	return err
+}
+
+// SendChatAction tells the user a chat action (typing, uploading a photo,
+// etc.) is in progress for roughly 5 seconds.
+func (t *TelegramAPI) SendChatAction(ctx context.Context, chatID int64, action ChatAction) error {
+	_, err := t.sendWithRetry(ctx, "sendChatAction", SendChatActionRequest{ChatID: chatID, Action: action})
+	return err
+}
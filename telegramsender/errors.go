@@ -0,0 +1,96 @@
+package telegramsender
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ResponseParameters mirrors Telegram's optional response_parameters
+// object, giving extra context for certain errors: MigrateToChatID when a
+// group has been upgraded to a supergroup, and RetryAfter for flood
+// control (429) responses.
+type ResponseParameters struct {
+	MigrateToChatID int64
+	RetryAfter      time.Duration
+}
+
+// rawResponseParameters is the wire shape of response_parameters, where
+// retry_after arrives as whole seconds rather than a time.Duration.
+type rawResponseParameters struct {
+	MigrateToChatID int64 `json:"migrate_to_chat_id,omitempty"`
+	RetryAfter      int   `json:"retry_after,omitempty"`
+}
+
+// APIError is returned by the send pipeline whenever Telegram responds
+// with ok:false. It preserves the numeric error code, description, and
+// any response_parameters so callers can react precisely instead of
+// string-matching a formatted error message.
+type APIError struct {
+	Code        int
+	Description string
+	Parameters  *ResponseParameters
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram API error: %d %s", e.Code, e.Description)
+}
+
+// toAPIError builds the APIError for a non-OK TelegramResponse. retryAfterHeader
+// is the HTTP Retry-After header, used as a fallback when Telegram didn't
+// also include retry_after in response_parameters.
+func (r *TelegramResponse) toAPIError(retryAfterHeader string) *APIError {
+	apiErr := &APIError{Code: r.ErrorCode, Description: r.Description}
+
+	if r.Parameters != nil {
+		apiErr.Parameters = &ResponseParameters{MigrateToChatID: r.Parameters.MigrateToChatID}
+		if r.Parameters.RetryAfter > 0 {
+			apiErr.Parameters.RetryAfter = time.Duration(r.Parameters.RetryAfter) * time.Second
+		}
+	}
+
+	if (apiErr.Parameters == nil || apiErr.Parameters.RetryAfter == 0) && retryAfterHeader != "" {
+		if seconds, err := time.ParseDuration(retryAfterHeader + "s"); err == nil {
+			if apiErr.Parameters == nil {
+				apiErr.Parameters = &ResponseParameters{}
+			}
+			apiErr.Parameters.RetryAfter = seconds
+		}
+	}
+
+	return apiErr
+}
+
+// IsBlockedByUser reports whether err is a 403 Forbidden response, which
+// Telegram returns when the user has blocked the bot.
+func IsBlockedByUser(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == 403
+}
+
+// IsChatNotFound reports whether err is a 400 "chat not found" response.
+func IsChatNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == 400 && strings.Contains(strings.ToLower(apiErr.Description), "chat not found")
+}
+
+// IsFloodWait reports whether err is a 429 Too Many Requests response and,
+// if so, how long Telegram asked the caller to wait before retrying.
+func IsFloodWait(err error) (time.Duration, bool) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 429 {
+		return 0, false
+	}
+	if apiErr.Parameters != nil && apiErr.Parameters.RetryAfter > 0 {
+		return apiErr.Parameters.RetryAfter, true
+	}
+	return 0, true
+}
+
+// migratable is implemented by request payloads that can be redirected to
+// a new chat ID, so the send pipeline can transparently retry against
+// migrate_to_chat_id when a group upgrades to a supergroup.
+type migratable interface {
+	withChatID(chatID int64) interface{}
+}
@@ -0,0 +1,83 @@
+package telegramsender
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestToAPIErrorUsesResponseParameters(t *testing.T) {
+	resp := &TelegramResponse{
+		ErrorCode:   429,
+		Description: "Too Many Requests",
+		Parameters:  &rawResponseParameters{RetryAfter: 5},
+	}
+
+	apiErr := resp.toAPIError("")
+
+	if apiErr.Code != 429 || apiErr.Description != "Too Many Requests" {
+		t.Fatalf("unexpected APIError: %+v", apiErr)
+	}
+	if apiErr.Parameters == nil || apiErr.Parameters.RetryAfter != 5*time.Second {
+		t.Fatalf("Parameters.RetryAfter = %+v, want 5s", apiErr.Parameters)
+	}
+}
+
+func TestToAPIErrorFallsBackToRetryAfterHeader(t *testing.T) {
+	resp := &TelegramResponse{ErrorCode: 429, Description: "Too Many Requests"}
+
+	apiErr := resp.toAPIError("3")
+
+	if apiErr.Parameters == nil || apiErr.Parameters.RetryAfter != 3*time.Second {
+		t.Fatalf("Parameters.RetryAfter = %+v, want 3s from header fallback", apiErr.Parameters)
+	}
+}
+
+func TestToAPIErrorResponseParametersWinOverHeader(t *testing.T) {
+	resp := &TelegramResponse{
+		ErrorCode:   429,
+		Description: "Too Many Requests",
+		Parameters:  &rawResponseParameters{RetryAfter: 5},
+	}
+
+	apiErr := resp.toAPIError("99")
+
+	if apiErr.Parameters.RetryAfter != 5*time.Second {
+		t.Fatalf("Parameters.RetryAfter = %v, want 5s (response_parameters should win)", apiErr.Parameters.RetryAfter)
+	}
+}
+
+func TestIsBlockedByUser(t *testing.T) {
+	err := &APIError{Code: 403, Description: "Forbidden: bot was blocked by the user"}
+	if !IsBlockedByUser(err) {
+		t.Error("IsBlockedByUser = false, want true for 403")
+	}
+	if IsBlockedByUser(errors.New("unrelated")) {
+		t.Error("IsBlockedByUser = true for a non-APIError")
+	}
+}
+
+func TestIsChatNotFound(t *testing.T) {
+	err := &APIError{Code: 400, Description: "Bad Request: chat not found"}
+	if !IsChatNotFound(err) {
+		t.Error("IsChatNotFound = false, want true")
+	}
+
+	other := &APIError{Code: 400, Description: "Bad Request: message text is empty"}
+	if IsChatNotFound(other) {
+		t.Error("IsChatNotFound = true for unrelated 400")
+	}
+}
+
+func TestIsFloodWait(t *testing.T) {
+	err := &APIError{Code: 429, Description: "Too Many Requests", Parameters: &ResponseParameters{RetryAfter: 7 * time.Second}}
+
+	delay, ok := IsFloodWait(err)
+	if !ok || delay != 7*time.Second {
+		t.Fatalf("IsFloodWait = (%v, %v), want (7s, true)", delay, ok)
+	}
+
+	if _, ok := IsFloodWait(&APIError{Code: 400}); ok {
+		t.Error("IsFloodWait = true for non-429 error")
+	}
+}
@@ -0,0 +1,304 @@
+package telegramsender
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/ristretto"
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/time/rate"
+)
+
+// DefaultFileCacheMaxBytes is the suggested cache size budget (1 GiB) for
+// NewFileServer when the caller has no more specific sizing requirement.
+const DefaultFileCacheMaxBytes = 1 << 30
+
+/* ---------- Telegram getFile ---------- */
+
+// fileInfoRequest is the payload for Telegram's getFile method.
+type fileInfoRequest struct {
+	FileID string `json:"file_id"`
+}
+
+// FileInfo is the result of GetFile. FilePath is valid for at least an hour
+// per Telegram's Bot API docs and is what builds the
+// https://api.telegram.org/file/bot<token>/<file_path> download URL.
+type FileInfo struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	FileSize     int64  `json:"file_size,omitempty"`
+	FilePath     string `json:"file_path,omitempty"`
+}
+
+// GetFile resolves fileID to its current FilePath and size.
+func (t *TelegramAPI) GetFile(ctx context.Context, fileID string) (*FileInfo, error) {
+	telegramResp, err := t.sendWithRetry(ctx, "getFile", fileInfoRequest{FileID: fileID})
+	if err != nil {
+		return nil, err
+	}
+
+	var info FileInfo
+	if err := json.Unmarshal(telegramResp.Result, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse result: %w", err)
+	}
+
+	return &info, nil
+}
+
+/* ---------- FileServer ---------- */
+
+// fileCacheEntry is what FileServer keeps in its in-process cache, keyed by
+// file_id.
+type fileCacheEntry struct {
+	data        []byte
+	contentType string
+	etag        string
+}
+
+// FileServer fronts Telegram's getFile/file-download endpoints with an
+// in-process, byte-sized LRU cache, so bots that repeatedly forward the same
+// media (avatars, stickers, common attachments) don't re-download it from
+// Telegram on every request. Mount it at a path such as /fileid/ with a
+// trailing wildcard; it expects requests shaped /fileid/{file_id}.{ext}.
+type FileServer struct {
+	logger    *slog.Logger
+	api       *TelegramAPI
+	cache     *ristretto.Cache
+	ipLimiter *ipRateLimiter
+	fetchOnce singleflight.Group
+}
+
+// NewFileServer builds a FileServer backed by api, caching up to
+// cacheMaxBytes of downloaded file content and admitting at most
+// perIPRateLimit requests/sec (burst 1) from any single client IP, evicting
+// idle per-IP limiters after idleTTL.
+func NewFileServer(logger *slog.Logger, api *TelegramAPI, cacheMaxBytes int64, perIPRateLimit float64, idleTTL time.Duration) (*FileServer, error) {
+	cache, err := ristretto.NewCache(&ristretto.Config{
+		NumCounters: 1e7,
+		MaxCost:     cacheMaxBytes,
+		BufferItems: 64,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file cache: %w", err)
+	}
+
+	return &FileServer{
+		logger:    logger,
+		api:       api,
+		cache:     cache,
+		ipLimiter: newIPRateLimiter(perIPRateLimit, 1, idleTTL),
+	}, nil
+}
+
+// ServeHTTP serves the file identified by the request path's {file_id},
+// fetching and caching it from Telegram on a miss and honoring
+// If-None-Match against the cached content hash on a hit.
+func (f *FileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !f.ipLimiter.allow(clientIP(r)) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+
+	fileID, ok := parseFileIDPath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	entry, err := f.lookup(r.Context(), fileID)
+	if err != nil {
+		f.logger.Error("file server lookup failed", "file_id", fileID, "error", err)
+		http.Error(w, "failed to fetch file", http.StatusBadGateway)
+		return
+	}
+
+	quotedETag := `"` + entry.etag + `"`
+	if match := r.Header.Get("If-None-Match"); match != "" && match == quotedETag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("ETag", quotedETag)
+	w.Header().Set("Content-Type", entry.contentType)
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("Content-Length", strconv.Itoa(len(entry.data)))
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(entry.data)
+}
+
+// lookup returns the cached entry for fileID, fetching it from Telegram on
+// a cache miss. Concurrent misses for the same fileID (a viral sticker or
+// avatar forwarded by many chats at once) are deduplicated through
+// fetchOnce so only one of them actually calls Telegram.
+func (f *FileServer) lookup(ctx context.Context, fileID string) (*fileCacheEntry, error) {
+	if cached, ok := f.cache.Get(fileID); ok {
+		return cached.(*fileCacheEntry), nil
+	}
+
+	result, err, _ := f.fetchOnce.Do(fileID, func() (interface{}, error) {
+		entry, err := f.fetch(ctx, fileID)
+		if err != nil {
+			return nil, err
+		}
+		f.cache.Set(fileID, entry, int64(len(entry.data)))
+		return entry, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*fileCacheEntry), nil
+}
+
+// fetch resolves fileID via getFile and downloads its bytes from
+// BaseURL's /file/bot<token>/<file_path> endpoint. Only the getFile call
+// passes through the Bot API rate limiter and circuit breaker; the download
+// itself goes straight through the HTTP client.
+func (f *FileServer) fetch(ctx context.Context, fileID string) (*fileCacheEntry, error) {
+	info, err := f.api.GetFile(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("getFile: %w", err)
+	}
+	if info.FilePath == "" {
+		return nil, fmt.Errorf("getFile returned no file_path for %s", fileID)
+	}
+
+	url := fmt.Sprintf("%s/file/bot%s/%s", f.api.config.BaseURL, f.api.config.BotToken, info.FilePath)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download request: %w", err)
+	}
+
+	resp, err := f.api.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download failed: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read download body: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return &fileCacheEntry{
+		data:        data,
+		contentType: contentTypeForPath(info.FilePath),
+		etag:        fmt.Sprintf("%x", sum),
+	}, nil
+}
+
+// parseFileIDPath extracts {file_id} from a request path shaped
+// /fileid/{file_id}.{ext}, tolerating a missing extension.
+func parseFileIDPath(path string) (string, bool) {
+	name := strings.TrimPrefix(path, "/fileid/")
+	if name == "" || name == path {
+		return "", false
+	}
+	if idx := strings.LastIndex(name, "."); idx > 0 {
+		name = name[:idx]
+	}
+	return name, true
+}
+
+// contentTypeForPath guesses a MIME type from a Telegram file_path's
+// extension, falling back to a generic binary stream.
+func contentTypeForPath(path string) string {
+	if ct := mime.TypeByExtension(filepath.Ext(path)); ct != "" {
+		return ct
+	}
+	return "application/octet-stream"
+}
+
+// clientIP extracts the caller's IP from r.RemoteAddr for per-IP rate
+// limiting.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+/* ---------- per-IP rate limiting ---------- */
+
+// ipRateLimiter caps requests per client IP for the public FileServer
+// handler, evicting limiters idle past idleTTL to bound memory. It mirrors
+// chatRateLimiter's lazy-create-and-sweep approach but rejects over-limit
+// requests outright instead of making the caller wait, since it guards a
+// synchronous HTTP handler rather than an outbound send loop.
+type ipRateLimiter struct {
+	mu      sync.Mutex
+	entries map[string]*chatLimiterEntry
+	limit   rate.Limit
+	burst   int
+	idleTTL time.Duration
+}
+
+func newIPRateLimiter(limit float64, burst int, idleTTL time.Duration) *ipRateLimiter {
+	return &ipRateLimiter{
+		entries: make(map[string]*chatLimiterEntry),
+		limit:   rate.Limit(limit),
+		burst:   burst,
+		idleTTL: idleTTL,
+	}
+}
+
+// allow reports whether ip's limiter currently admits a request.
+func (l *ipRateLimiter) allow(ip string) bool {
+	return l.get(ip).Allow()
+}
+
+// get returns the limiter for ip, lazily creating one on first use and
+// sweeping idle entries while it holds the lock.
+func (l *ipRateLimiter) get(ip string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.evictIdleLocked()
+
+	entry, ok := l.entries[ip]
+	if !ok {
+		entry = &chatLimiterEntry{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.entries[ip] = entry
+	}
+	entry.lastUsed = time.Now()
+	return entry.limiter
+}
+
+// evictIdleLocked removes limiters unused for longer than idleTTL. Callers
+// must hold l.mu.
+func (l *ipRateLimiter) evictIdleLocked() {
+	if l.idleTTL <= 0 {
+		return
+	}
+	cutoff := time.Now().Add(-l.idleTTL)
+	for ip, entry := range l.entries {
+		if entry.lastUsed.Before(cutoff) {
+			delete(l.entries, ip)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package telegramsender
+
+import "testing"
+
+func TestParseFileIDPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		wantID   string
+		wantOK   bool
+		testName string
+	}{
+		{path: "/fileid/AgADBAAD.jpg", wantID: "AgADBAAD", wantOK: true, testName: "with extension"},
+		{path: "/fileid/AgADBAAD", wantID: "AgADBAAD", wantOK: true, testName: "without extension"},
+		{path: "/fileid/", wantID: "", wantOK: false, testName: "empty file id"},
+		{path: "/other/AgADBAAD.jpg", wantID: "", wantOK: false, testName: "wrong prefix"},
+		{path: "/fileid/.jpg", wantID: ".jpg", wantOK: true, testName: "leading dot not treated as extension separator"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			id, ok := parseFileIDPath(tt.path)
+			if id != tt.wantID || ok != tt.wantOK {
+				t.Errorf("parseFileIDPath(%q) = (%q, %v), want (%q, %v)", tt.path, id, ok, tt.wantID, tt.wantOK)
+			}
+		})
+	}
+}
@@ -36,11 +36,33 @@ func ValidateConfig(cfg *Config) error {
 		return errors.New("RETRY_BACKOFF_FACTOR must be positive")
 	case cfg.MaxRetries < 0:
 		return errors.New("MAX_RETRIES must be non-negative")
+	case cfg.PerChatRateLimit <= 0:
+		return errors.New("PER_CHAT_RATE_LIMIT must be positive")
+	case cfg.PerGroupRateLimit <= 0:
+		return errors.New("PER_GROUP_RATE_LIMIT must be positive")
+	case cfg.PerChatLimiterIdleTTL <= 0:
+		return errors.New("PER_CHAT_LIMITER_IDLE_TTL must be positive")
+	case !validRetryStrategy(cfg.RetryStrategy):
+		return errors.New("RETRY_STRATEGY must be one of: exponential, full_jitter, decorrelated_jitter")
+	case cfg.RetryRandomizationFactor <= 0:
+		return errors.New("RETRY_RANDOMIZATION_FACTOR must be positive")
+	case cfg.RetryMaxElapsedTime < 0:
+		return errors.New("RETRY_MAX_ELAPSED_TIME must be non-negative")
 	default:
 		return nil
 	}
 }
 
+// validRetryStrategy reports whether s is a known RetryStrategy.
+func validRetryStrategy(s RetryStrategy) bool {
+	switch s {
+	case RetryStrategyExponential, RetryStrategyFullJitter, RetryStrategyDecorrelatedJitter:
+		return true
+	default:
+		return false
+	}
+}
+
 // validateBotToken checks if the token has the correct format.
 // Telegram bot tokens follow the pattern: 123456789:ABCDefGhIJKlmNoPQRsTUVwxyZ
 func validateBotToken(token string) bool {
@@ -0,0 +1,77 @@
+package telegramsender
+
+// InlineKeyboardButton represents a single button of an inline keyboard.
+// Exactly one of CallbackData, URL, SwitchInlineQuery, or
+// SwitchInlineQueryCurrentChat should be set.
+type InlineKeyboardButton struct {
+	Text                         string `json:"text"`
+	CallbackData                 string `json:"callback_data,omitempty"`
+	URL                          string `json:"url,omitempty"`
+	SwitchInlineQuery            string `json:"switch_inline_query,omitempty"`
+	SwitchInlineQueryCurrentChat string `json:"switch_inline_query_current_chat,omitempty"`
+}
+
+// InlineKeyboardMarkup is an inline keyboard attached to a message, passed
+// as a MessageRequest's ReplyMarkup.
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+// NewInlineKeyboard creates an empty inline keyboard to build up with AddRow.
+func NewInlineKeyboard() *InlineKeyboardMarkup {
+	return &InlineKeyboardMarkup{}
+}
+
+// AddRow appends a row of buttons to the keyboard and returns the markup
+// so calls can be chained.
+func (m *InlineKeyboardMarkup) AddRow(buttons ...InlineKeyboardButton) *InlineKeyboardMarkup {
+	m.InlineKeyboard = append(m.InlineKeyboard, buttons)
+	return m
+}
+
+// NewCallbackButton builds a button that sends callbackData back to the
+// bot as a CallbackQuery when pressed.
+func NewCallbackButton(text, callbackData string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, CallbackData: callbackData}
+}
+
+// NewURLButton builds a button that opens url in the user's client.
+func NewURLButton(text, url string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, URL: url}
+}
+
+// KeyboardButton represents a single button of a custom reply keyboard.
+type KeyboardButton struct {
+	Text            string `json:"text"`
+	RequestContact  bool   `json:"request_contact,omitempty"`
+	RequestLocation bool   `json:"request_location,omitempty"`
+}
+
+// ReplyKeyboardMarkup replaces the user's regular keyboard with a custom
+// one, passed as a MessageRequest's ReplyMarkup.
+type ReplyKeyboardMarkup struct {
+	Keyboard        [][]KeyboardButton `json:"keyboard"`
+	ResizeKeyboard  bool               `json:"resize_keyboard,omitempty"`
+	OneTimeKeyboard bool               `json:"one_time_keyboard,omitempty"`
+	Selective       bool               `json:"selective,omitempty"`
+}
+
+// NewReplyKeyboard creates an empty, auto-resizing reply keyboard to build
+// up with AddRow.
+func NewReplyKeyboard() *ReplyKeyboardMarkup {
+	return &ReplyKeyboardMarkup{ResizeKeyboard: true}
+}
+
+// AddRow appends a row of buttons to the keyboard and returns the markup
+// so calls can be chained.
+func (m *ReplyKeyboardMarkup) AddRow(buttons ...KeyboardButton) *ReplyKeyboardMarkup {
+	m.Keyboard = append(m.Keyboard, buttons)
+	return m
+}
+
+// ReplyKeyboardRemove hides any custom keyboard currently shown to the user,
+// passed as a MessageRequest's ReplyMarkup.
+type ReplyKeyboardRemove struct {
+	RemoveKeyboard bool `json:"remove_keyboard"`
+	Selective      bool `json:"selective,omitempty"`
+}
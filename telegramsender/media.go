@@ -0,0 +1,355 @@
+package telegramsender
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+)
+
+/* ---------- types ---------- */
+
+// InputFile identifies a file to send to Telegram. Exactly one of FileID,
+// URL, or Reader should be set: FileID re-uses a file already known to
+// Telegram, URL has Telegram fetch the file server-side, and Reader
+// streams raw bytes to upload (FileName is required in that case).
+type InputFile struct {
+	FileID   string
+	URL      string
+	Reader   io.Reader
+	FileName string
+}
+
+func (f InputFile) isUpload() bool { return f.Reader != nil }
+
+// value returns the plain string Telegram accepts for this file when it
+// is not a raw upload (a file_id or URL).
+func (f InputFile) value() string {
+	if f.FileID != "" {
+		return f.FileID
+	}
+	return f.URL
+}
+
+// PhotoRequest is the payload for SendPhoto.
+type PhotoRequest struct {
+	ChatID              int64
+	Photo               InputFile
+	Caption             string
+	ParseMode           string
+	DisableNotification bool
+	ReplyToMessageID    int
+	ReplyMarkup         interface{}
+}
+
+// DocumentRequest is the payload for SendDocument.
+type DocumentRequest struct {
+	ChatID              int64
+	Document            InputFile
+	Caption             string
+	ParseMode           string
+	DisableNotification bool
+	ReplyToMessageID    int
+	ReplyMarkup         interface{}
+}
+
+// VideoRequest is the payload for SendVideo.
+type VideoRequest struct {
+	ChatID              int64
+	Video               InputFile
+	Caption             string
+	ParseMode           string
+	DisableNotification bool
+	ReplyToMessageID    int
+	ReplyMarkup         interface{}
+}
+
+// AudioRequest is the payload for SendAudio.
+type AudioRequest struct {
+	ChatID              int64
+	Audio               InputFile
+	Caption             string
+	ParseMode           string
+	DisableNotification bool
+	ReplyToMessageID    int
+	ReplyMarkup         interface{}
+}
+
+// InputMediaPhoto and InputMediaVideo describe one item of a media group
+// sent via SendMediaGroup. Media must reference an existing file_id or URL;
+// see MediaGroupRequest for why raw uploads aren't supported here.
+type InputMediaPhoto struct {
+	Type      string `json:"type"`
+	Media     string `json:"media"`
+	Caption   string `json:"caption,omitempty"`
+	ParseMode string `json:"parse_mode,omitempty"`
+}
+
+type InputMediaVideo struct {
+	Type      string `json:"type"`
+	Media     string `json:"media"`
+	Caption   string `json:"caption,omitempty"`
+	ParseMode string `json:"parse_mode,omitempty"`
+}
+
+// NewInputMediaPhoto builds a media group entry for an already-known file
+// (file_id or URL).
+func NewInputMediaPhoto(file InputFile, caption string) InputMediaPhoto {
+	return InputMediaPhoto{Type: "photo", Media: file.value(), Caption: caption}
+}
+
+// NewInputMediaVideo builds a media group entry for an already-known file
+// (file_id or URL).
+func NewInputMediaVideo(file InputFile, caption string) InputMediaVideo {
+	return InputMediaVideo{Type: "video", Media: file.value(), Caption: caption}
+}
+
+// MediaGroupRequest is the payload for SendMediaGroup. Raw-upload media
+// items are not supported; build InputMediaPhoto/InputMediaVideo entries
+// from file_id or URL references.
+type MediaGroupRequest struct {
+	ChatID              int64         `json:"chat_id"`
+	Media               []interface{} `json:"media"`
+	DisableNotification bool          `json:"disable_notification,omitempty"`
+	ReplyToMessageID    int           `json:"reply_to_message_id,omitempty"`
+}
+
+func (r MediaGroupRequest) chatID() int64 { return r.ChatID }
+
+func (r MediaGroupRequest) withChatID(chatID int64) interface{} {
+	r.ChatID = chatID
+	return r
+}
+
+/* ---------- public methods ---------- */
+
+// SendPhoto sends a photo, uploading raw bytes if request.Photo.Reader is
+// set or referencing an existing file_id/URL otherwise.
+func (t *TelegramAPI) SendPhoto(ctx context.Context, request PhotoRequest) (*MessageResult, error) {
+	return t.sendMediaMessage(ctx, "sendPhoto", request)
+}
+
+// SendDocument sends a general file, uploading raw bytes if
+// request.Document.Reader is set or referencing an existing file_id/URL
+// otherwise.
+func (t *TelegramAPI) SendDocument(ctx context.Context, request DocumentRequest) (*MessageResult, error) {
+	return t.sendMediaMessage(ctx, "sendDocument", request)
+}
+
+// SendVideo sends a video, uploading raw bytes if request.Video.Reader is
+// set or referencing an existing file_id/URL otherwise.
+func (t *TelegramAPI) SendVideo(ctx context.Context, request VideoRequest) (*MessageResult, error) {
+	return t.sendMediaMessage(ctx, "sendVideo", request)
+}
+
+// SendAudio sends an audio file, uploading raw bytes if
+// request.Audio.Reader is set or referencing an existing file_id/URL
+// otherwise.
+func (t *TelegramAPI) SendAudio(ctx context.Context, request AudioRequest) (*MessageResult, error) {
+	return t.sendMediaMessage(ctx, "sendAudio", request)
+}
+
+// SendMediaGroup sends an album of 2-10 photos/videos as a single message
+// group.
+func (t *TelegramAPI) SendMediaGroup(ctx context.Context, request MediaGroupRequest) ([]MessageResult, error) {
+	telegramResp, err := t.sendWithRetry(ctx, "sendMediaGroup", request)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []MessageResult
+	if err := json.Unmarshal(telegramResp.Result, &results); err != nil {
+		return nil, fmt.Errorf("failed to parse result: %w", err)
+	}
+
+	return results, nil
+}
+
+/* ---------- private helpers ---------- */
+
+// sendMediaMessage runs any single-file media request (photo/document/
+// video/audio) through the shared retry loop and parses the resulting
+// message.
+func (t *TelegramAPI) sendMediaMessage(ctx context.Context, method string, request interface{}) (*MessageResult, error) {
+	telegramResp, err := t.sendWithRetry(ctx, method, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var msgResult MessageResult
+	if err := json.Unmarshal(telegramResp.Result, &msgResult); err != nil {
+		return nil, fmt.Errorf("failed to parse result: %w", err)
+	}
+
+	return &msgResult, nil
+}
+
+// writeMediaFields writes the fields common to every single-file media
+// request to a multipart body.
+func writeMediaFields(w *multipart.Writer, chatID int64, caption, parseMode string, disableNotification bool, replyToMessageID int, replyMarkup interface{}) error {
+	if err := w.WriteField("chat_id", fmt.Sprintf("%d", chatID)); err != nil {
+		return err
+	}
+	if caption != "" {
+		if err := w.WriteField("caption", caption); err != nil {
+			return err
+		}
+	}
+	if parseMode != "" {
+		if err := w.WriteField("parse_mode", parseMode); err != nil {
+			return err
+		}
+	}
+	if disableNotification {
+		if err := w.WriteField("disable_notification", "true"); err != nil {
+			return err
+		}
+	}
+	if replyToMessageID != 0 {
+		if err := w.WriteField("reply_to_message_id", fmt.Sprintf("%d", replyToMessageID)); err != nil {
+			return err
+		}
+	}
+	if replyMarkup != nil {
+		markup, err := json.Marshal(replyMarkup)
+		if err != nil {
+			return fmt.Errorf("failed to marshal reply markup: %w", err)
+		}
+		if err := w.WriteField("reply_markup", string(markup)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeInputFile writes field either as a plain file_id/URL value or, for
+// raw uploads, as a streamed multipart file part.
+func writeInputFile(w *multipart.Writer, field string, file InputFile) error {
+	if !file.isUpload() {
+		return w.WriteField(field, file.value())
+	}
+
+	part, err := w.CreateFormFile(field, file.FileName)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file.Reader); err != nil {
+		return fmt.Errorf("failed to stream %s: %w", field, err)
+	}
+	return nil
+}
+
+/* ---------- JSON/multipart encoding ---------- */
+
+func (r PhotoRequest) chatID() int64 { return r.ChatID }
+
+func (r PhotoRequest) withChatID(chatID int64) interface{} {
+	r.ChatID = chatID
+	return r
+}
+
+func (r PhotoRequest) hasUpload() bool { return r.Photo.isUpload() }
+
+func (r PhotoRequest) writeMultipart(w *multipart.Writer) error {
+	if err := writeMediaFields(w, r.ChatID, r.Caption, r.ParseMode, r.DisableNotification, r.ReplyToMessageID, r.ReplyMarkup); err != nil {
+		return err
+	}
+	return writeInputFile(w, "photo", r.Photo)
+}
+
+func (r PhotoRequest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ChatID              int64       `json:"chat_id"`
+		Photo               string      `json:"photo"`
+		Caption             string      `json:"caption,omitempty"`
+		ParseMode           string      `json:"parse_mode,omitempty"`
+		DisableNotification bool        `json:"disable_notification,omitempty"`
+		ReplyToMessageID    int         `json:"reply_to_message_id,omitempty"`
+		ReplyMarkup         interface{} `json:"reply_markup,omitempty"`
+	}{r.ChatID, r.Photo.value(), r.Caption, r.ParseMode, r.DisableNotification, r.ReplyToMessageID, r.ReplyMarkup})
+}
+
+func (r DocumentRequest) chatID() int64 { return r.ChatID }
+
+func (r DocumentRequest) withChatID(chatID int64) interface{} {
+	r.ChatID = chatID
+	return r
+}
+
+func (r DocumentRequest) hasUpload() bool { return r.Document.isUpload() }
+
+func (r DocumentRequest) writeMultipart(w *multipart.Writer) error {
+	if err := writeMediaFields(w, r.ChatID, r.Caption, r.ParseMode, r.DisableNotification, r.ReplyToMessageID, r.ReplyMarkup); err != nil {
+		return err
+	}
+	return writeInputFile(w, "document", r.Document)
+}
+
+func (r DocumentRequest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ChatID              int64       `json:"chat_id"`
+		Document            string      `json:"document"`
+		Caption             string      `json:"caption,omitempty"`
+		ParseMode           string      `json:"parse_mode,omitempty"`
+		DisableNotification bool        `json:"disable_notification,omitempty"`
+		ReplyToMessageID    int         `json:"reply_to_message_id,omitempty"`
+		ReplyMarkup         interface{} `json:"reply_markup,omitempty"`
+	}{r.ChatID, r.Document.value(), r.Caption, r.ParseMode, r.DisableNotification, r.ReplyToMessageID, r.ReplyMarkup})
+}
+
+func (r VideoRequest) chatID() int64 { return r.ChatID }
+
+func (r VideoRequest) withChatID(chatID int64) interface{} {
+	r.ChatID = chatID
+	return r
+}
+
+func (r VideoRequest) hasUpload() bool { return r.Video.isUpload() }
+
+func (r VideoRequest) writeMultipart(w *multipart.Writer) error {
+	if err := writeMediaFields(w, r.ChatID, r.Caption, r.ParseMode, r.DisableNotification, r.ReplyToMessageID, r.ReplyMarkup); err != nil {
+		return err
+	}
+	return writeInputFile(w, "video", r.Video)
+}
+
+func (r VideoRequest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ChatID              int64       `json:"chat_id"`
+		Video               string      `json:"video"`
+		Caption             string      `json:"caption,omitempty"`
+		ParseMode           string      `json:"parse_mode,omitempty"`
+		DisableNotification bool        `json:"disable_notification,omitempty"`
+		ReplyToMessageID    int         `json:"reply_to_message_id,omitempty"`
+		ReplyMarkup         interface{} `json:"reply_markup,omitempty"`
+	}{r.ChatID, r.Video.value(), r.Caption, r.ParseMode, r.DisableNotification, r.ReplyToMessageID, r.ReplyMarkup})
+}
+
+func (r AudioRequest) chatID() int64 { return r.ChatID }
+
+func (r AudioRequest) withChatID(chatID int64) interface{} {
+	r.ChatID = chatID
+	return r
+}
+
+func (r AudioRequest) hasUpload() bool { return r.Audio.isUpload() }
+
+func (r AudioRequest) writeMultipart(w *multipart.Writer) error {
+	if err := writeMediaFields(w, r.ChatID, r.Caption, r.ParseMode, r.DisableNotification, r.ReplyToMessageID, r.ReplyMarkup); err != nil {
+		return err
+	}
+	return writeInputFile(w, "audio", r.Audio)
+}
+
+func (r AudioRequest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ChatID              int64       `json:"chat_id"`
+		Audio               string      `json:"audio"`
+		Caption             string      `json:"caption,omitempty"`
+		ParseMode           string      `json:"parse_mode,omitempty"`
+		DisableNotification bool        `json:"disable_notification,omitempty"`
+		ReplyToMessageID    int         `json:"reply_to_message_id,omitempty"`
+		ReplyMarkup         interface{} `json:"reply_markup,omitempty"`
+	}{r.ChatID, r.Audio.value(), r.Caption, r.ParseMode, r.DisableNotification, r.ReplyToMessageID, r.ReplyMarkup})
+}
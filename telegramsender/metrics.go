@@ -0,0 +1,113 @@
+package telegramsender
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds the Prometheus instruments for a TelegramAPI's send
+// pipeline: requests, retries, circuit-breaker transitions, rate-limit
+// waits, HTTP/Telegram error distributions, and end-to-end latency. Build
+// one with NewMetrics and pass it to NewTelegramAPIWithObservability.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requestsTotal        *prometheus.CounterVec
+	retriesTotal         *prometheus.CounterVec
+	breakerStateChanges  *prometheus.CounterVec
+	rateLimitWaitSeconds *prometheus.HistogramVec
+	httpStatusTotal      *prometheus.CounterVec
+	telegramErrorTotal   *prometheus.CounterVec
+	sendLatencySeconds   *prometheus.HistogramVec
+	inFlightRequests     prometheus.Gauge
+}
+
+// NewMetrics creates and registers the Prometheus instruments on a fresh
+// registry. Mount Handler() to expose them for scraping.
+func NewMetrics() *Metrics {
+	registry := prometheus.NewRegistry()
+
+	m := &Metrics{
+		registry: registry,
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "telegramsender",
+			Name:      "requests_total",
+			Help:      "Bot API requests, by method and outcome (success/error).",
+		}, []string{"method", "outcome"}),
+		retriesTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "telegramsender",
+			Name:      "retries_total",
+			Help:      "Retry attempts issued by the send pipeline, by method.",
+		}, []string{"method"}),
+		breakerStateChanges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "telegramsender",
+			Name:      "breaker_state_changes_total",
+			Help:      "Circuit breaker state transitions, by from/to state.",
+		}, []string{"from", "to"}),
+		rateLimitWaitSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "telegramsender",
+			Name:      "rate_limit_wait_seconds",
+			Help:      "Time spent waiting on a rate limiter before dispatch.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"scope"}),
+		httpStatusTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "telegramsender",
+			Name:      "http_status_total",
+			Help:      "HTTP responses from the Bot API, by method and status code.",
+		}, []string{"method", "status_code"}),
+		telegramErrorTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "telegramsender",
+			Name:      "telegram_error_total",
+			Help:      "Telegram error_code values returned by the Bot API, by method.",
+		}, []string{"method", "error_code"}),
+		sendLatencySeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "telegramsender",
+			Name:      "send_latency_seconds",
+			Help:      "End-to-end latency of a send call, including retries.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		inFlightRequests: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "telegramsender",
+			Name:      "in_flight_requests",
+			Help:      "Send calls currently in flight, across all methods.",
+		}),
+	}
+
+	registry.MustRegister(
+		m.requestsTotal,
+		m.retriesTotal,
+		m.breakerStateChanges,
+		m.rateLimitWaitSeconds,
+		m.httpStatusTotal,
+		m.telegramErrorTotal,
+		m.sendLatencySeconds,
+		m.inFlightRequests,
+	)
+
+	return m
+}
+
+// registerChatLimiterCardinality exposes the number of per-chat limiters
+// currently held in memory. Called once by NewTelegramAPIWithObservability
+// since the count is only available once the chat limiter exists.
+func (m *Metrics) registerChatLimiterCardinality(count func() int) {
+	gauge := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "telegramsender",
+		Name:      "chat_limiter_cardinality",
+		Help:      "Number of per-chat rate limiters currently held in memory.",
+	}, func() float64 { return float64(count()) })
+	m.registry.MustRegister(gauge)
+}
+
+// Handler returns an http.Handler serving these metrics in the Prometheus
+// exposition format, for mounting at e.g. /metrics.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func errorCodeLabel(code int) string {
+	return fmt.Sprintf("%d", code)
+}
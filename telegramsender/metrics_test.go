@@ -0,0 +1,96 @@
+package telegramsender
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func testAPIWithMetrics(t *testing.T, srv *httptest.Server) (*TelegramAPI, *Metrics) {
+	t.Helper()
+	metrics := NewMetrics()
+	cfg := testQueueAPI(t, srv).config
+	tracer := noop.NewTracerProvider().Tracer("telegramsender-test")
+	return NewTelegramAPIWithObservability(testLogger(), cfg, metrics, tracer), metrics
+}
+
+func TestMetricsRecordSuccessfulSend(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":1}}`)
+	}))
+	defer srv.Close()
+
+	api, metrics := testAPIWithMetrics(t, srv)
+	if _, err := api.SendMessage(context.Background(), MessageRequest{ChatID: 1, Text: "hi"}); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+
+	if got := testutil.ToFloat64(metrics.requestsTotal.WithLabelValues("sendMessage", "success")); got != 1 {
+		t.Errorf("requestsTotal{sendMessage,success} = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.httpStatusTotal.WithLabelValues("sendMessage", "200")); got != 1 {
+		t.Errorf("httpStatusTotal{sendMessage,200} = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.inFlightRequests); got != 0 {
+		t.Errorf("inFlightRequests = %v, want 0 after send completes", got)
+	}
+	if count := testutil.CollectAndCount(metrics.sendLatencySeconds); count == 0 {
+		t.Error("sendLatencySeconds has no observations")
+	}
+}
+
+func TestMetricsRecordTelegramError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`)
+	}))
+	defer srv.Close()
+
+	api, metrics := testAPIWithMetrics(t, srv)
+	if _, err := api.SendMessage(context.Background(), MessageRequest{ChatID: 1, Text: "hi"}); err == nil {
+		t.Fatal("SendMessage: want error for a non-OK response, got nil")
+	}
+
+	if got := testutil.ToFloat64(metrics.requestsTotal.WithLabelValues("sendMessage", "error")); got != 1 {
+		t.Errorf("requestsTotal{sendMessage,error} = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(metrics.telegramErrorTotal.WithLabelValues("sendMessage", "400")); got != 1 {
+		t.Errorf("telegramErrorTotal{sendMessage,400} = %v, want 1", got)
+	}
+}
+
+func TestMetricsChatLimiterCardinality(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":1}}`)
+	}))
+	defer srv.Close()
+
+	api, metrics := testAPIWithMetrics(t, srv)
+	if _, err := api.SendMessage(context.Background(), MessageRequest{ChatID: 42, Text: "hi"}); err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+
+	gauge, err := metrics.registry.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	found := false
+	for _, mf := range gauge {
+		if mf.GetName() == "telegramsender_chat_limiter_cardinality" {
+			found = true
+			if got := mf.Metric[0].GetGauge().GetValue(); got != 1 {
+				t.Errorf("chat_limiter_cardinality = %v, want 1", got)
+			}
+		}
+	}
+	if !found {
+		t.Error("chat_limiter_cardinality metric not registered")
+	}
+}
@@ -0,0 +1,262 @@
+package telegramsender
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+/* ---------- types ---------- */
+
+// Job is a unit of outbound work persisted by Queue: a Bot API method name
+// plus its already-marshaled JSON payload, as produced by one of the typed
+// Send*/Edit*/Delete* request types.
+type Job struct {
+	ID          string          `json:"id"`
+	Method      string          `json:"method"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Deadline    time.Time       `json:"deadline,omitempty"`
+	Attempt     int             `json:"attempt"`
+	MaxAttempts int             `json:"max_attempts"`
+}
+
+// NewJob builds a Job from a typed request, ready for Queue.Enqueue. id
+// should be a caller-supplied idempotency key; retention bounds how long
+// the job may sit in the queue before it is dropped as a dead letter.
+func NewJob(id, method string, request interface{}, retention time.Duration, maxAttempts int) (Job, error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return Job{}, fmt.Errorf("failed to marshal job %s payload: %w", id, err)
+	}
+
+	now := time.Now()
+	return Job{
+		ID:          id,
+		Method:      method,
+		Payload:     payload,
+		CreatedAt:   now,
+		Deadline:    now.Add(retention),
+		MaxAttempts: maxAttempts,
+	}, nil
+}
+
+// DeadLetter is a job that exhausted MaxAttempts or passed its Deadline
+// without being delivered.
+type DeadLetter struct {
+	Job      Job       `json:"job"`
+	Reason   string    `json:"reason"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// DefaultQueueWorkers is the concurrency NewQueue uses when called with
+// workers <= 0: enough to keep one slow chat's retry loop from stalling
+// delivery to every other chat, without unbounded fan-out against
+// Telegram's own rate limits.
+const DefaultQueueWorkers = 8
+
+// Queue decouples callers from transient Telegram outages: jobs are
+// persisted via Store before being drained through API, so they survive
+// process restarts and absorb bursts far above the rate-limit ceiling.
+// Failed jobs past MaxAttempts land in Store's dead-letter table, inspect
+// them with DeadLetters.
+type Queue struct {
+	logger  *slog.Logger
+	api     *TelegramAPI
+	store   QueueStore
+	workers int
+
+	pending chan Job
+
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+	stop     chan struct{}
+
+	timersMu sync.Mutex
+	timers   map[*time.Timer]struct{}
+}
+
+// NewQueue creates a Queue that drains jobs through api and persists them
+// via store, processing up to workers jobs concurrently (DefaultQueueWorkers
+// if workers <= 0) so one job stuck in a slow retry loop can't stall every
+// other chat's delivery behind it. Call Resume once at startup to re-hydrate
+// jobs left pending by a previous process, then Run to start draining.
+func NewQueue(logger *slog.Logger, api *TelegramAPI, store QueueStore, workers int) *Queue {
+	if workers <= 0 {
+		workers = DefaultQueueWorkers
+	}
+	return &Queue{
+		logger:  logger,
+		api:     api,
+		store:   store,
+		workers: workers,
+		pending: make(chan Job, 256),
+		stop:    make(chan struct{}),
+		timers:  make(map[*time.Timer]struct{}),
+	}
+}
+
+// Enqueue persists job and schedules it for delivery.
+func (q *Queue) Enqueue(ctx context.Context, job Job) error {
+	if err := q.store.Save(ctx, job); err != nil {
+		return fmt.Errorf("failed to enqueue job %s: %w", job.ID, err)
+	}
+	return q.schedule(ctx, job)
+}
+
+// Resume re-hydrates jobs left pending by a previous process and schedules
+// them for delivery. Call this once at startup, before Run.
+func (q *Queue) Resume(ctx context.Context) error {
+	jobs, err := q.store.Pending(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to resume pending jobs: %w", err)
+	}
+	for _, job := range jobs {
+		if err := q.schedule(ctx, job); err != nil {
+			return err
+		}
+	}
+	q.logger.Info("resumed pending jobs", "count", len(jobs))
+	return nil
+}
+
+// Run starts q.workers concurrent workers draining pending jobs, each
+// dispatching through API's rate-limited, circuit-breaker-guarded retry
+// path, and blocks until ctx is canceled or Stop is called. Running jobs
+// concurrently keeps one chat stuck in a slow retry loop from blocking
+// delivery to every other chat behind it in the shared pending channel.
+func (q *Queue) Run(ctx context.Context) {
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.worker(ctx)
+	}
+	q.wg.Wait()
+}
+
+func (q *Queue) worker(ctx context.Context) {
+	defer q.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.stop:
+			return
+		case job := <-q.pending:
+			q.process(ctx, job)
+		}
+	}
+}
+
+// Stop halts Run, cancels any pending retry timers, and waits for any
+// in-flight job to finish processing.
+func (q *Queue) Stop() {
+	q.stopOnce.Do(func() { close(q.stop) })
+	q.cancelTimers()
+	q.wg.Wait()
+}
+
+// DeadLetters returns jobs that exhausted their retries or retention
+// window, for inspection or manual replay.
+func (q *Queue) DeadLetters(ctx context.Context) ([]DeadLetter, error) {
+	return q.store.DeadLetters(ctx)
+}
+
+/* ---------- private methods ---------- */
+
+func (q *Queue) schedule(ctx context.Context, job Job) error {
+	select {
+	case q.pending <- job:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *Queue) process(ctx context.Context, job Job) {
+	if !job.Deadline.IsZero() && time.Now().After(job.Deadline) {
+		q.deadLetter(ctx, job, "retention window expired")
+		return
+	}
+
+	job.Attempt++
+	if err := q.api.Dispatch(ctx, job.Method, job.Payload); err != nil {
+		q.retryOrDeadLetter(ctx, job, err)
+		return
+	}
+
+	if err := q.store.Delete(ctx, job.ID); err != nil {
+		q.logger.Error("failed to delete delivered job", "job_id", job.ID, "error", err)
+	}
+}
+
+func (q *Queue) retryOrDeadLetter(ctx context.Context, job Job, deliveryErr error) {
+	if job.Attempt >= job.MaxAttempts {
+		q.deadLetter(ctx, job, deliveryErr.Error())
+		return
+	}
+
+	if err := q.store.Save(ctx, job); err != nil {
+		q.logger.Error("failed to persist job attempt", "job_id", job.ID, "error", err)
+	}
+	q.logger.Warn("job delivery failed, will retry",
+		"job_id", job.ID,
+		"attempt", job.Attempt,
+		"max_attempts", job.MaxAttempts,
+		"error", deliveryErr)
+
+	backoff := time.Duration(job.Attempt) * 2 * time.Second
+	if backoff > time.Minute {
+		backoff = time.Minute
+	}
+
+	var t *time.Timer
+	t = time.AfterFunc(backoff, func() {
+		q.untrackTimer(t)
+		select {
+		case <-q.stop:
+			return
+		default:
+		}
+		_ = q.schedule(ctx, job)
+	})
+	q.trackTimer(t)
+}
+
+// trackTimer registers t so Stop can cancel it if Queue shuts down before
+// it fires, instead of leaving it to fire into a channel nothing drains.
+func (q *Queue) trackTimer(t *time.Timer) {
+	q.timersMu.Lock()
+	defer q.timersMu.Unlock()
+	q.timers[t] = struct{}{}
+}
+
+func (q *Queue) untrackTimer(t *time.Timer) {
+	q.timersMu.Lock()
+	defer q.timersMu.Unlock()
+	delete(q.timers, t)
+}
+
+// cancelTimers stops every outstanding retry timer. A timer already firing
+// when Stop runs is left to its callback, which exits immediately once it
+// observes q.stop closed.
+func (q *Queue) cancelTimers() {
+	q.timersMu.Lock()
+	defer q.timersMu.Unlock()
+	for t := range q.timers {
+		t.Stop()
+	}
+}
+
+func (q *Queue) deadLetter(ctx context.Context, job Job, reason string) {
+	dl := DeadLetter{Job: job, Reason: reason, FailedAt: time.Now()}
+	if err := q.store.SaveDeadLetter(ctx, dl); err != nil {
+		q.logger.Error("failed to persist dead letter", "job_id", job.ID, "error", err)
+	}
+	if err := q.store.Delete(ctx, job.ID); err != nil {
+		q.logger.Error("failed to delete dead-lettered job", "job_id", job.ID, "error", err)
+	}
+	q.logger.Error("job moved to dead-letter queue", "job_id", job.ID, "reason", reason)
+}
@@ -0,0 +1,154 @@
+package telegramsender
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// QueueStore persists pending jobs and dead letters so Queue can resume
+// after a crash. FileQueueStore is the default; a Redis- or Postgres-
+// backed store can satisfy the same interface.
+type QueueStore interface {
+	Save(ctx context.Context, job Job) error
+	Delete(ctx context.Context, id string) error
+	Pending(ctx context.Context) ([]Job, error)
+	SaveDeadLetter(ctx context.Context, dl DeadLetter) error
+	DeadLetters(ctx context.Context) ([]DeadLetter, error)
+}
+
+// FileQueueStore persists each pending job and dead letter as one JSON
+// file under dir's "pending" and "dead" subdirectories, giving crash-
+// resumable delivery without an external database dependency.
+type FileQueueStore struct {
+	mu     sync.Mutex
+	dir    string
+	logger *slog.Logger
+}
+
+// NewFileQueueStore creates the pending/dead-letter directories under dir
+// if needed and returns a store rooted there. logger receives a warning
+// for any pending file Pending finds corrupted, which it skips rather
+// than failing the whole scan.
+func NewFileQueueStore(logger *slog.Logger, dir string) (*FileQueueStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "pending"), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create pending job dir: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "dead"), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create dead-letter dir: %w", err)
+	}
+	return &FileQueueStore{dir: dir, logger: logger}, nil
+}
+
+func (s *FileQueueStore) pendingPath(id string) string {
+	return filepath.Join(s.dir, "pending", id+".json")
+}
+
+// Save persists job by writing to a temp file and renaming it into place,
+// so a crash mid-write never leaves a partially-written, unparseable
+// pending file behind for Pending to trip over on the next Resume.
+func (s *FileQueueStore) Save(ctx context.Context, job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job %s: %w", job.ID, err)
+	}
+	path := s.pendingPath(job.ID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to persist job %s: %w", job.ID, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to commit job %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+func (s *FileQueueStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.pendingPath(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete job %s: %w", id, err)
+	}
+	return nil
+}
+
+// Pending returns every job left pending by a previous process. Leftover
+// .tmp files from an interrupted Save are ignored, and a .json job file
+// that can't be read or parsed is logged and skipped rather than failing
+// the whole scan, so one corrupt entry never blocks every other job from
+// being resumed.
+func (s *FileQueueStore) Pending(ctx context.Context) ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(filepath.Join(s.dir, "pending"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending jobs: %w", err)
+	}
+
+	jobs := make([]Job, 0, len(entries))
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, "pending", entry.Name()))
+		if err != nil {
+			s.logger.Warn("skipping unreadable pending job", "file", entry.Name(), "error", err)
+			continue
+		}
+		var job Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			s.logger.Warn("skipping corrupt pending job", "file", entry.Name(), "error", err)
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func (s *FileQueueStore) SaveDeadLetter(ctx context.Context, dl DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(dl)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter %s: %w", dl.Job.ID, err)
+	}
+	path := filepath.Join(s.dir, "dead", dl.Job.ID+".json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to persist dead letter %s: %w", dl.Job.ID, err)
+	}
+	return nil
+}
+
+func (s *FileQueueStore) DeadLetters(ctx context.Context) ([]DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(filepath.Join(s.dir, "dead"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letters: %w", err)
+	}
+
+	letters := make([]DeadLetter, 0, len(entries))
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(s.dir, "dead", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dead letter %s: %w", entry.Name(), err)
+		}
+		var dl DeadLetter
+		if err := json.Unmarshal(data, &dl); err != nil {
+			return nil, fmt.Errorf("failed to parse dead letter %s: %w", entry.Name(), err)
+		}
+		letters = append(letters, dl)
+	}
+	return letters, nil
+}
@@ -0,0 +1,244 @@
+package telegramsender
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testQueueAPI builds a TelegramAPI pointed at srv with retries and
+// backoff tuned down so queue-level tests run fast.
+func testQueueAPI(t *testing.T, srv *httptest.Server) *TelegramAPI {
+	t.Helper()
+	cfg := &Config{
+		BotToken:                 "123456:testtokentesttokentesttokentest",
+		BaseURL:                  srv.URL,
+		RequestTimeout:           time.Second,
+		KeepAlive:                time.Second,
+		MaxIdleConns:             1,
+		IdleConnTimeout:          time.Second,
+		RateLimitRequests:        100,
+		RateLimitBurst:           100,
+		PerChatRateLimit:         100,
+		PerGroupRateLimit:        100,
+		PerChatLimiterIdleTTL:    time.Minute,
+		BreakerMaxRequests:       10,
+		BreakerInterval:          time.Minute,
+		BreakerTimeout:           time.Minute,
+		MaxRetries:               0,
+		RetryInitialBackoff:      time.Millisecond,
+		RetryMaxBackoff:          time.Millisecond,
+		RetryBackoffFactor:       2,
+		RetryStrategy:            RetryStrategyFullJitter,
+		RetryRandomizationFactor: 0.1,
+		RetryMaxElapsedTime:      time.Second,
+		LogFilePath:              "/dev/null",
+	}
+	return NewTelegramAPI(testLogger(), cfg)
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func newTestQueue(t *testing.T, api *TelegramAPI) (*Queue, *FileQueueStore) {
+	t.Helper()
+	store, err := NewFileQueueStore(testLogger(), t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileQueueStore: %v", err)
+	}
+	return NewQueue(testLogger(), api, store, 1), store
+}
+
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("condition not met within %v", timeout)
+}
+
+func TestQueueEnqueueSuccessDeletesJob(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":true,"result":{}}`)
+	}))
+	defer srv.Close()
+
+	q, store := newTestQueue(t, testQueueAPI(t, srv))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx)
+	defer q.Stop()
+
+	job, err := NewJob("job-1", "sendMessage", map[string]interface{}{"chat_id": 1, "text": "hi"}, time.Hour, 3)
+	if err != nil {
+		t.Fatalf("NewJob: %v", err)
+	}
+	if err := q.Enqueue(ctx, job); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	waitFor(t, time.Second, func() bool {
+		jobs, err := store.Pending(ctx)
+		return err == nil && len(jobs) == 0
+	})
+}
+
+func TestQueueRetriesThenDeadLettersOnPersistentFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`)
+	}))
+	defer srv.Close()
+
+	q, store := newTestQueue(t, testQueueAPI(t, srv))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx)
+	defer q.Stop()
+
+	job, err := NewJob("job-2", "sendMessage", map[string]interface{}{"chat_id": 1, "text": "hi"}, time.Hour, 1)
+	if err != nil {
+		t.Fatalf("NewJob: %v", err)
+	}
+	if err := q.Enqueue(ctx, job); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	waitFor(t, time.Second, func() bool {
+		letters, err := store.DeadLetters(ctx)
+		return err == nil && len(letters) == 1
+	})
+
+	jobs, err := store.Pending(ctx)
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(jobs) != 0 {
+		t.Errorf("Pending() = %d jobs, want 0 after dead-lettering", len(jobs))
+	}
+}
+
+func TestQueueRetriesSucceedBeforeDeadLetter(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			fmt.Fprint(w, `{"ok":false,"error_code":500,"description":"Internal Server Error"}`)
+			return
+		}
+		fmt.Fprint(w, `{"ok":true,"result":{}}`)
+	}))
+	defer srv.Close()
+
+	q, store := newTestQueue(t, testQueueAPI(t, srv))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx)
+	defer q.Stop()
+
+	job, err := NewJob("job-3", "sendMessage", map[string]interface{}{"chat_id": 1, "text": "hi"}, time.Hour, 3)
+	if err != nil {
+		t.Fatalf("NewJob: %v", err)
+	}
+	if err := q.Enqueue(ctx, job); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	// retryOrDeadLetter's backoff is at least 2s for the first retry.
+	waitFor(t, 5*time.Second, func() bool {
+		jobs, err := store.Pending(ctx)
+		return err == nil && len(jobs) == 0
+	})
+
+	letters, err := store.DeadLetters(ctx)
+	if err != nil {
+		t.Fatalf("DeadLetters: %v", err)
+	}
+	if len(letters) != 0 {
+		t.Errorf("DeadLetters() = %d, want 0 after a successful retry", len(letters))
+	}
+}
+
+func TestQueueResumeRehydratesPendingJobs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":true,"result":{}}`)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	store, err := NewFileQueueStore(testLogger(), dir)
+	if err != nil {
+		t.Fatalf("NewFileQueueStore: %v", err)
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{"chat_id": 1, "text": "hi"})
+	if err != nil {
+		t.Fatalf("marshal payload: %v", err)
+	}
+	job := Job{ID: "job-4", Method: "sendMessage", Payload: payload, Deadline: time.Now().Add(time.Hour), MaxAttempts: 3}
+	if err := store.Save(context.Background(), job); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Simulate a fresh process: a new Queue over the same on-disk store.
+	q := NewQueue(testLogger(), testQueueAPI(t, srv), store, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := q.Resume(ctx); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	go q.Run(ctx)
+	defer q.Stop()
+
+	waitFor(t, time.Second, func() bool {
+		jobs, err := store.Pending(ctx)
+		return err == nil && len(jobs) == 0
+	})
+}
+
+func TestQueueDeadLettersExpiredRetentionWithoutDispatch(t *testing.T) {
+	var called int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&called, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":true,"result":{}}`)
+	}))
+	defer srv.Close()
+
+	q, store := newTestQueue(t, testQueueAPI(t, srv))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx)
+	defer q.Stop()
+
+	job, err := NewJob("job-5", "sendMessage", map[string]interface{}{"chat_id": 1, "text": "hi"}, -time.Hour, 3)
+	if err != nil {
+		t.Fatalf("NewJob: %v", err)
+	}
+	if err := q.Enqueue(ctx, job); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	waitFor(t, time.Second, func() bool {
+		letters, err := store.DeadLetters(ctx)
+		return err == nil && len(letters) == 1
+	})
+
+	if atomic.LoadInt32(&called) != 0 {
+		t.Errorf("Telegram was called %d times for a job past its deadline, want 0", called)
+	}
+}
@@ -0,0 +1,108 @@
+package telegramsender
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// captureServer records the Content-Type and raw body of the last request
+// it received and always answers with a successful response whose result
+// carries message_id 1.
+// lastReq and lastBody are only valid for reading after the request under
+// test has completed.
+func captureServer(t *testing.T) (srv *httptest.Server, lastReq **http.Request, lastBody *[]byte) {
+	t.Helper()
+	lastReq = new(*http.Request)
+	lastBody = new([]byte)
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*lastReq = r
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("read request body: %v", err)
+		}
+		*lastBody = body
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":true,"result":{"message_id":1}}`)
+	}))
+	return srv, lastReq, lastBody
+}
+
+func TestSendPhotoByFileIDUsesJSON(t *testing.T) {
+	srv, _, body := captureServer(t)
+	defer srv.Close()
+
+	api := testQueueAPI(t, srv)
+	_, err := api.SendPhoto(context.Background(), PhotoRequest{ChatID: 1, Photo: InputFile{FileID: "abc123"}})
+	if err != nil {
+		t.Fatalf("SendPhoto: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(*body, &decoded); err != nil {
+		t.Fatalf("request body is not JSON: %v (body: %s)", err, *body)
+	}
+	if decoded["photo"] != "abc123" {
+		t.Errorf("photo field = %v, want file_id abc123", decoded["photo"])
+	}
+}
+
+func TestSendPhotoWithReaderUsesMultipart(t *testing.T) {
+	srv, lastReq, body := captureServer(t)
+	defer srv.Close()
+
+	api := testQueueAPI(t, srv)
+	content := "fake-image-bytes"
+	_, err := api.SendPhoto(context.Background(), PhotoRequest{
+		ChatID: 1,
+		Photo:  InputFile{Reader: strings.NewReader(content), FileName: "photo.jpg"},
+	})
+	if err != nil {
+		t.Fatalf("SendPhoto: %v", err)
+	}
+
+	ct := (*lastReq).Header.Get("Content-Type")
+	if !strings.HasPrefix(ct, "multipart/form-data") {
+		t.Fatalf("Content-Type = %q, want multipart/form-data", ct)
+	}
+	if !strings.Contains(string(*body), content) {
+		t.Errorf("request body does not contain uploaded file bytes")
+	}
+	if !strings.Contains(string(*body), `name="photo"`) {
+		t.Errorf("request body missing photo form part")
+	}
+}
+
+func TestSendMessageWithInlineKeyboardMarshalsReplyMarkup(t *testing.T) {
+	srv, _, body := captureServer(t)
+	defer srv.Close()
+
+	api := testQueueAPI(t, srv)
+	kb := NewInlineKeyboard().AddRow(NewCallbackButton("Yes", "yes"), NewURLButton("Docs", "https://example.com"))
+
+	_, err := api.SendMessage(context.Background(), MessageRequest{ChatID: 1, Text: "hi", ReplyMarkup: kb})
+	if err != nil {
+		t.Fatalf("SendMessage: %v", err)
+	}
+
+	var decoded struct {
+		ReplyMarkup InlineKeyboardMarkup `json:"reply_markup"`
+	}
+	if err := json.Unmarshal(*body, &decoded); err != nil {
+		t.Fatalf("request body is not JSON: %v (body: %s)", err, *body)
+	}
+	if len(decoded.ReplyMarkup.InlineKeyboard) != 1 || len(decoded.ReplyMarkup.InlineKeyboard[0]) != 2 {
+		t.Fatalf("unexpected inline keyboard shape: %+v", decoded.ReplyMarkup)
+	}
+	if decoded.ReplyMarkup.InlineKeyboard[0][0].CallbackData != "yes" {
+		t.Errorf("first button callback_data = %q, want yes", decoded.ReplyMarkup.InlineKeyboard[0][0].CallbackData)
+	}
+	if decoded.ReplyMarkup.InlineKeyboard[0][1].URL != "https://example.com" {
+		t.Errorf("second button url = %q, want https://example.com", decoded.ReplyMarkup.InlineKeyboard[0][1].URL)
+	}
+}
@@ -8,35 +8,53 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
-	"math"
+	"mime/multipart"
 	"net"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/sony/gobreaker"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	"golang.org/x/time/rate"
 )
 
+// noopTracer is used whenever a TelegramAPI is built without
+// NewTelegramAPIWithObservability, so invoke can always call
+// t.tracerOrNoop() without nil-checking at every call site.
+var noopTracer = noop.NewTracerProvider().Tracer("telegramsender")
+
 /* ---------- types ---------- */
 
 type TelegramAPI struct {
-	logger     *slog.Logger
-	config     *Config
-	httpClient *http.Client
-	limiter    *rate.Limiter
-	breaker    *gobreaker.CircuitBreaker
+	logger      *slog.Logger
+	config      *Config
+	httpClient  *http.Client
+	limiter     *rate.Limiter
+	chatLimiter *chatRateLimiter
+	breaker     *gobreaker.CircuitBreaker
+	metrics     *Metrics
+	tracer      trace.Tracer
+}
+
+// tracerOrNoop returns t.tracer, falling back to a no-op tracer so invoke
+// never has to nil-check before starting a span.
+func (t *TelegramAPI) tracerOrNoop() trace.Tracer {
+	if t.tracer != nil {
+		return t.tracer
+	}
+	return noopTracer
 }
 
 type TelegramResponse struct {
-	OK          bool            `json:"ok"`
-	Result      json.RawMessage `json:"result,omitempty"`
-	ErrorCode   int             `json:"error_code,omitempty"`
-	Description string          `json:"description,omitempty"`
-	// RetryAfter is not part of the API response, but used internally
-	// to pass the Retry-After header value for rate limit handling
-	RetryAfter  time.Duration   `json:"-"`
+	OK          bool                   `json:"ok"`
+	Result      json.RawMessage        `json:"result,omitempty"`
+	ErrorCode   int                    `json:"error_code,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  *rawResponseParameters `json:"parameters,omitempty"`
 }
 
 type MessageRequest struct {
@@ -53,9 +71,34 @@ type MessageResult struct {
 	MessageID int `json:"message_id"`
 }
 
+func (r MessageRequest) chatID() int64 { return r.ChatID }
+
+func (r MessageRequest) withChatID(chatID int64) interface{} {
+	r.ChatID = chatID
+	return r
+}
+
 /* ---------- constructor ---------- */
 
 func NewTelegramAPI(logger *slog.Logger, config *Config) *TelegramAPI {
+	return newTelegramAPI(logger, config, nil, nil)
+}
+
+// NewTelegramAPIWithObservability builds a TelegramAPI that additionally
+// records Prometheus metrics on metrics (requests, retries, breaker state
+// transitions, rate-limit waits, HTTP/Telegram error codes, and send
+// latency) and wraps each call in an OpenTelemetry span via tracer that
+// propagates the caller's trace context. Mount metrics.Handler() at
+// /metrics to expose them for scraping.
+func NewTelegramAPIWithObservability(logger *slog.Logger, config *Config, metrics *Metrics, tracer trace.Tracer) *TelegramAPI {
+	api := newTelegramAPI(logger, config, metrics, tracer)
+	if metrics != nil {
+		metrics.registerChatLimiterCardinality(api.chatLimiter.len)
+	}
+	return api
+}
+
+func newTelegramAPI(logger *slog.Logger, config *Config, metrics *Metrics, tracer trace.Tracer) *TelegramAPI {
 	// Configure transport for connection pooling
 	transport := &http.Transport{
 		DialContext: (&net.Dialer{
@@ -90,15 +133,21 @@ func NewTelegramAPI(logger *slog.Logger, config *Config) *TelegramAPI {
 				"name", name,
 				"from", from.String(),
 				"to", to.String())
+			if metrics != nil {
+				metrics.breakerStateChanges.WithLabelValues(from.String(), to.String()).Inc()
+			}
 		},
 	}
 
 	return &TelegramAPI{
-		logger:     logger,
-		config:     config,
-		httpClient: httpClient,
-		limiter:    rate.NewLimiter(rate.Limit(config.RateLimitRequests), config.RateLimitBurst),
-		breaker:    gobreaker.NewCircuitBreaker(cbSettings),
+		logger:      logger,
+		config:      config,
+		httpClient:  httpClient,
+		limiter:     rate.NewLimiter(rate.Limit(config.RateLimitRequests), config.RateLimitBurst),
+		chatLimiter: newChatRateLimiter(config.PerChatRateLimit, config.PerGroupRateLimit, config.PerChatLimiterIdleTTL),
+		breaker:     gobreaker.NewCircuitBreaker(cbSettings),
+		metrics:     metrics,
+		tracer:      tracer,
 	}
 }
 
@@ -106,19 +155,51 @@ func NewTelegramAPI(logger *slog.Logger, config *Config) *TelegramAPI {
 
 // SendMessage sends a text message to the specified chat
 func (t *TelegramAPI) SendMessage(ctx context.Context, request MessageRequest) (*MessageResult, error) {
+	telegramResp, err := t.sendWithRetry(ctx, "sendMessage", request)
+	if err != nil {
+		return nil, err
+	}
+
+	var msgResult MessageResult
+	if err := json.Unmarshal(telegramResp.Result, &msgResult); err != nil {
+		return nil, fmt.Errorf("failed to parse result: %w", err)
+	}
+
+	return &msgResult, nil
+}
+
+/* ---------- private methods ---------- */
+
+// sendWithRetry runs method/payload through the rate limiter, circuit
+// breaker, and retry/backoff loop shared by every Bot API call. It returns
+// the raw (already-OK) TelegramResponse so callers can unmarshal whatever
+// result shape the method produces.
+func (t *TelegramAPI) sendWithRetry(ctx context.Context, method string, payload interface{}) (*TelegramResponse, error) {
 	if err := ValidateConfig(t.config); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
 
-	var result *MessageResult
+	if t.metrics != nil {
+		t.metrics.inFlightRequests.Inc()
+		defer t.metrics.inFlightRequests.Dec()
+
+		start := time.Now()
+		defer func() {
+			t.metrics.sendLatencySeconds.WithLabelValues(method).Observe(time.Since(start).Seconds())
+		}()
+	}
+
+	var result *TelegramResponse
 	var err error
 	var serverRetryDelay time.Duration
+	bo := newBackoff(t.config)
 
-	// Apply retry with exponential backoff
+	// Apply retry with the configured backoff strategy
 	for attempt := 0; attempt <= t.config.MaxRetries; attempt++ {
 		// Main request (first attempt or after backoff)
-		result, err = t.sendMessageOnce(ctx, request)
+		result, err = t.invoke(ctx, method, payload, attempt)
 		if err == nil {
+			t.recordOutcome(method, "success")
 			return result, nil
 		}
 
@@ -127,34 +208,53 @@ func (t *TelegramAPI) SendMessage(ctx context.Context, request MessageRequest) (
 			break
 		}
 
+		// A migrate_to_chat_id response means the chat (usually a group
+		// upgraded to a supergroup) now lives under a new ID; rewrite the
+		// payload and retry immediately rather than treating it as a
+		// normal failure.
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.Parameters != nil && apiErr.Parameters.MigrateToChatID != 0 {
+			if mg, ok := payload.(migratable); ok {
+				t.logger.Info("chat migrated, retrying with new chat id",
+					"method", method,
+					"new_chat_id", apiErr.Parameters.MigrateToChatID,
+					"attempt", attempt)
+				payload = mg.withChatID(apiErr.Parameters.MigrateToChatID)
+				continue
+			}
+		}
+
 		// Check if the error is retryable
 		if !t.isRetryable(err) {
 			t.logger.Error("non-retryable error",
 				"error", err,
+				"method", method,
 				"attempt", attempt)
+			t.recordOutcome(method, "error")
 			return nil, err
 		}
 
-		// Check for rate limit response with Retry-After header
-		var telegramErr *TelegramResponse
-		if errors.As(err, &telegramErr) && telegramErr.RetryAfter > 0 {
-			serverRetryDelay = telegramErr.RetryAfter
+		if t.metrics != nil {
+			t.metrics.retriesTotal.WithLabelValues(method).Inc()
+		}
+
+		// Check for a server-supplied retry delay (flood control)
+		if apiErr != nil && apiErr.Parameters != nil && apiErr.Parameters.RetryAfter > 0 {
+			serverRetryDelay = apiErr.Parameters.RetryAfter
 			t.logger.Warn("received rate limit response",
 				"retry_after", serverRetryDelay.String(),
+				"method", method,
 				"attempt", attempt)
 		} else {
 			serverRetryDelay = 0
 		}
 
-		// Determine backoff time for next attempt
-		var backoff time.Duration
-		if serverRetryDelay > 0 {
-			backoff = serverRetryDelay
-		} else {
-			backoff = t.calculateBackoff(attempt + 1)
-		}
+		// Determine backoff time for next attempt; a server-supplied delay
+		// always wins over the strategy's own jitter.
+		backoff := bo.NextBackoff(attempt+1, serverRetryDelay)
 
 		t.logger.Info("retrying request",
+			"method", method,
 			"attempt", attempt+1,
 			"backoff", backoff.String(),
 			"using_server_delay", serverRetryDelay > 0)
@@ -167,59 +267,181 @@ func (t *TelegramAPI) SendMessage(ctx context.Context, request MessageRequest) (
 			// Continue to next attempt
 		}
 	}
-	
+
 	// If we've exhausted all retries, return the last error
+	t.recordOutcome(method, "error")
 	return nil, fmt.Errorf("max retries exceeded: %w", err)
 }
 
-/* ---------- private methods ---------- */
+// recordOutcome is a no-op when metrics weren't configured.
+func (t *TelegramAPI) recordOutcome(method, outcome string) {
+	if t.metrics != nil {
+		t.metrics.requestsTotal.WithLabelValues(method, outcome).Inc()
+	}
+}
+
+// invoke performs a single rate-limited, circuit-breaker-guarded call to
+// the Telegram Bot API and returns the parsed response on success. It runs
+// inside a span that propagates ctx's trace and records method, chat_id,
+// and attempt as attributes, plus retry_after when the call fails with a
+// flood-control delay.
+func (t *TelegramAPI) invoke(ctx context.Context, method string, payload interface{}, attempt int) (*TelegramResponse, error) {
+	attrs := []attribute.KeyValue{
+		attribute.String("method", method),
+		attribute.Int("attempt", attempt),
+	}
+	if cs, ok := payload.(chatScoped); ok {
+		attrs = append(attrs, attribute.Int64("chat_id", cs.chatID()))
+	}
+	ctx, span := t.tracerOrNoop().Start(ctx, "telegramsender."+method, trace.WithAttributes(attrs...))
+	defer span.End()
+
+	// Per-chat rate limit check, ahead of the global one
+	if cs, ok := payload.(chatScoped); ok {
+		waitStart := time.Now()
+		err := t.chatLimiter.wait(ctx, cs.chatID())
+		if t.metrics != nil {
+			t.metrics.rateLimitWaitSeconds.WithLabelValues("chat").Observe(time.Since(waitStart).Seconds())
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return nil, fmt.Errorf("per-chat rate limit exceeded: %w", err)
+		}
+	}
 
-func (t *TelegramAPI) sendMessageOnce(ctx context.Context, request MessageRequest) (*MessageResult, error) {
 	// Rate limit check
-	if err := t.limiter.Wait(ctx); err != nil {
+	waitStart := time.Now()
+	err := t.limiter.Wait(ctx)
+	if t.metrics != nil {
+		t.metrics.rateLimitWaitSeconds.WithLabelValues("global").Observe(time.Since(waitStart).Seconds())
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return nil, fmt.Errorf("rate limit exceeded: %w", err)
 	}
 
 	// Use circuit breaker
 	resp, err := t.breaker.Execute(func() (interface{}, error) {
-		return t.executeRequest(ctx, "sendMessage", request)
+		return t.executeRequest(ctx, method, payload)
 	})
 
 	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.Parameters != nil && apiErr.Parameters.RetryAfter > 0 {
+			span.SetAttributes(attribute.Float64("retry_after", apiErr.Parameters.RetryAfter.Seconds()))
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
 
-	telegramResp := resp.(*TelegramResponse)
-	if !telegramResp.OK {
-		return nil, fmt.Errorf("telegram API error: %d %s", telegramResp.ErrorCode, telegramResp.Description)
+	return resp.(*TelegramResponse), nil
+}
+
+// Dispatch routes method/payload through the same rate-limited, circuit-
+// breaker-guarded retry path used by the typed Send*/Edit*/Delete* methods.
+// It exists for callers, such as Queue, that only have a Bot API method
+// name and an already-marshaled JSON payload to work with.
+func (t *TelegramAPI) Dispatch(ctx context.Context, method string, payload json.RawMessage) error {
+	var generic genericPayload
+	if err := json.Unmarshal(payload, &generic); err != nil {
+		return fmt.Errorf("failed to parse job payload: %w", err)
 	}
+	_, err := t.sendWithRetry(ctx, method, generic)
+	return err
+}
 
-	var msgResult MessageResult
-	if err := json.Unmarshal(telegramResp.Result, &msgResult); err != nil {
-		return nil, fmt.Errorf("failed to parse result: %w", err)
+// genericPayload carries an already-decoded JSON object through invoke
+// when the caller only has a method name and raw bytes (see Dispatch). It
+// always satisfies chatScoped: chatID reads a numeric chat_id field, so
+// per-chat rate limiting keeps working for dispatched jobs, and objects
+// without one all share the limiter for chat ID 0.
+type genericPayload map[string]interface{}
+
+func (g genericPayload) chatID() int64 {
+	if v, ok := g["chat_id"].(float64); ok {
+		return int64(v)
+	}
+	return 0
+}
+
+func (g genericPayload) withChatID(chatID int64) interface{} {
+	clone := make(genericPayload, len(g))
+	for k, v := range g {
+		clone[k] = v
 	}
+	clone["chat_id"] = chatID
+	return clone
+}
 
-	return &msgResult, nil
+// multipartPayload is implemented by requests that may carry raw file
+// uploads and must therefore be sent as multipart/form-data instead of
+// plain JSON.
+type multipartPayload interface {
+	hasUpload() bool
+	writeMultipart(w *multipart.Writer) error
 }
 
+// executeRequest dispatches payload as JSON or multipart/form-data
+// depending on whether it carries a raw file upload.
 func (t *TelegramAPI) executeRequest(ctx context.Context, method string, payload interface{}) (*TelegramResponse, error) {
+	if mp, ok := payload.(multipartPayload); ok && mp.hasUpload() {
+		return t.executeMultipartRequest(ctx, method, mp)
+	}
+	return t.executeJSONRequest(ctx, method, payload)
+}
+
+func (t *TelegramAPI) executeJSONRequest(ctx context.Context, method string, payload interface{}) (*TelegramResponse, error) {
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	// Build the actual URL with the token
+	return t.doRequest(ctx, method, "application/json", bytes.NewBuffer(jsonData))
+}
+
+// executeMultipartRequest encodes payload as multipart/form-data, streaming
+// any file contents directly from their io.Reader into the request body
+// via an io.Pipe instead of buffering the whole (possibly multi-GB) upload
+// in memory first.
+func (t *TelegramAPI) executeMultipartRequest(ctx context.Context, method string, payload multipartPayload) (*TelegramResponse, error) {
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+	writeDone := make(chan error, 1)
+
+	go func() {
+		err := payload.writeMultipart(w)
+		if err == nil {
+			err = w.Close()
+		}
+		writeDone <- err
+		pw.CloseWithError(err)
+	}()
+
+	resp, err := t.doRequest(ctx, method, w.FormDataContentType(), pr)
+	if writeErr := <-writeDone; writeErr != nil {
+		return nil, fmt.Errorf("failed to build multipart body: %w", writeErr)
+	}
+	return resp, err
+}
+
+// doRequest POSTs body to the given Bot API method and parses the common
+// TelegramResponse envelope, surfacing any Retry-After header for the
+// retry loop.
+func (t *TelegramAPI) doRequest(ctx context.Context, method, contentType string, body io.Reader) (*TelegramResponse, error) {
 	url := fmt.Sprintf("%s/bot%s/%s", t.config.BaseURL, t.config.BotToken, method)
-	
+
 	// Create a redacted URL for logging that hides the token
 	redactedURL := fmt.Sprintf("%s/bot[REDACTED]/%s", t.config.BaseURL, method)
-	
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request to %s: %w", redactedURL, err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := t.httpClient.Do(req)
@@ -228,54 +450,43 @@ func (t *TelegramAPI) executeRequest(ctx context.Context, method string, payload
 	}
 	defer resp.Body.Close()
 
+	if t.metrics != nil {
+		t.metrics.httpStatusTotal.WithLabelValues(method, strconv.Itoa(resp.StatusCode)).Inc()
+	}
+
 	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	// Parse response
 	var telegramResp TelegramResponse
-	if err := json.Unmarshal(body, &telegramResp); err != nil {
+	if err := json.Unmarshal(respBody, &telegramResp); err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	// Handle non-OK responses as errors
+	// Handle non-OK responses as a typed, structured error
 	if !telegramResp.OK {
-		// Add the Retry-After header if present (for rate limiting responses)
-		retryAfter := resp.Header.Get("Retry-After")
-		
+		apiErr := telegramResp.toAPIError(resp.Header.Get("Retry-After"))
+
+		if t.metrics != nil {
+			t.metrics.telegramErrorTotal.WithLabelValues(method, errorCodeLabel(apiErr.Code)).Inc()
+		}
+
 		t.logger.Error("telegram API error",
 			"method", method,
 			"url", redactedURL,
 			"status_code", resp.StatusCode,
-			"error_code", telegramResp.ErrorCode,
-			"description", telegramResp.Description,
-			"retry_after", retryAfter)
-		
-		// If this is a rate limit error and has a Retry-After header,
-		// attach it to the error to be used by retry logic
-		if telegramResp.ErrorCode == 429 && retryAfter != "" {
-			// Parse the Retry-After value (in seconds)
-			if seconds, err := strconv.Atoi(retryAfter); err == nil {
-				telegramResp.RetryAfter = time.Duration(seconds) * time.Second
-			}
-		}
+			"error_code", apiErr.Code,
+			"description", apiErr.Description)
+
+		return nil, apiErr
 	}
 
 	return &telegramResp, nil
 }
 
-func (t *TelegramAPI) calculateBackoff(attempt int) time.Duration {
-	backoff := t.config.RetryInitialBackoff * time.Duration(math.Pow(t.config.RetryBackoffFactor, float64(attempt-1)))
-	if backoff > t.config.RetryMaxBackoff {
-		backoff = t.config.RetryMaxBackoff
-	}
-	// Add jitter (±20%)
-	jitter := time.Duration(float64(backoff) * (0.8 + 0.4*float64(attempt%2)))
-	return jitter
-}
-
 func (t *TelegramAPI) isRetryable(err error) bool {
 	var netErr net.Error
 	if errors.As(err, &netErr) && netErr.Timeout() {
@@ -286,45 +497,12 @@ func (t *TelegramAPI) isRetryable(err error) bool {
 		return false
 	}
 
-	// Handle specific Telegram error codes that are retryable
 	// 429 - Too Many Requests
 	// 500, 502, 503, 504 - Server errors
-	if telegramErr := extractTelegramError(err); telegramErr != nil {
-		code := telegramErr.ErrorCode
-		return code == 429 || code >= 500 && code <= 504
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == 429 || (apiErr.Code >= 500 && apiErr.Code <= 504)
 	}
 
 	return false
-}
-
-func extractTelegramError(err error) *TelegramResponse {
-	// Check if the error message contains Telegram error information
-	errMsg := err.Error()
-	if strings.Contains(errMsg, "telegram API error") {
-		// This is a best-effort extraction from our formatted error message
-		if strings.Contains(errMsg, "403") {
-			return &TelegramResponse{
-				OK:          false,
-				ErrorCode:   403,
-				Description: "Forbidden",
-			}
-		} else if strings.Contains(errMsg, "429") {
-			return &TelegramResponse{
-				OK:          false,
-				ErrorCode:   429,
-				Description: "Too Many Requests",
-			}
-		} else if strings.Contains(errMsg, "500") || 
-		          strings.Contains(errMsg, "502") || 
-		          strings.Contains(errMsg, "503") || 
-		          strings.Contains(errMsg, "504") {
-			return &TelegramResponse{
-				OK:          false,
-				ErrorCode:   500,
-				Description: "Server Error",
-			}
-		}
-	}
-	
-	return nil
 }
\ No newline at end of file